@@ -0,0 +1,100 @@
+package ringcache
+
+import "sync"
+
+// asyncWriter implements WithAsyncPush: a single goroutine applies queued pushes in order.
+type asyncWriter[K comparable, V any] struct {
+	mu      sync.RWMutex // guards closed; held for reading while sending on ops
+	closed  bool
+	ops     chan asyncOp[K, V]
+	stopped chan struct{} // closed when the writer goroutine has drained ops and exited
+}
+
+// asyncOp is a queued push, or a drain marker if done is set.
+type asyncOp[K comparable, V any] struct {
+	entry Entry[K, V]
+	done  chan struct{}
+}
+
+// WithAsyncPush enables PushAsync: pushes are queued on a channel of size queue and applied in
+// order by one writer goroutine, so callers never wait for the cache lock, only for room in the
+// queue when it is full. Reads do not wait for the queue either and may briefly miss entries
+// that were pushed asynchronously but not applied yet; Flush waits for them. Eviction callbacks
+// and hooks of async pushes run on the writer goroutine, so they must not call PushAsync, Flush
+// or Close: the writer would wait on itself, for queue room only it frees or for a drain only it
+// performs, and deadlock. The goroutine runs until Close, which every cache created with this
+// option must eventually call. A queue of 0 disables async mode.
+func WithAsyncPush[K comparable, V any](queue int) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.asyncQueue = queue }
+}
+
+// newAsyncWriter starts the writer goroutine for c.
+func newAsyncWriter[K comparable, V any](c *RingCache[K, V], queue int) *asyncWriter[K, V] {
+	a := &asyncWriter[K, V]{
+		ops:     make(chan asyncOp[K, V], queue),
+		stopped: make(chan struct{}),
+	}
+	go func() {
+		defer close(a.stopped)
+		for op := range a.ops {
+			if op.done != nil {
+				close(op.done)
+				continue
+			}
+			c.Push(op.entry.Key, op.entry.Value)
+		}
+	}()
+	return a
+}
+
+// PushAsync queues a Push of (key, value) and returns without waiting for it to be applied.
+// Async pushes are applied in the order PushAsync was called, each exactly as Push would apply
+// it. It blocks only while the queue is full, so it must not be called from a callback run by
+// an async push (see WithAsyncPush). It returns ErrClosed after Close. Without WithAsyncPush
+// it simply calls Push.
+func (c *RingCache[K, V]) PushAsync(key K, value V) error {
+	if c.async == nil {
+		c.Push(key, value)
+		return nil
+	}
+	return c.async.send(asyncOp[K, V]{entry: Entry[K, V]{Key: key, Value: value}})
+}
+
+// Close applies the pushes still queued by PushAsync and stops the writer goroutine. Later
+// PushAsync calls return ErrClosed; every other method keeps working. Close is idempotent and
+// a no-op without WithAsyncPush; it always returns nil.
+func (c *RingCache[K, V]) Close() error {
+	if c.async == nil {
+		return nil
+	}
+	a := c.async
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.ops)
+	}
+	a.mu.Unlock()
+	<-a.stopped
+	return nil
+}
+
+// send queues op unless the writer is closed.
+func (a *asyncWriter[K, V]) send(op asyncOp[K, V]) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return ErrClosed
+	}
+	a.ops <- op
+	return nil
+}
+
+// wait returns once every op queued before the call has been applied.
+func (a *asyncWriter[K, V]) wait() {
+	done := make(chan struct{})
+	if a.send(asyncOp[K, V]{done: done}) != nil {
+		<-a.stopped // closed: Close drains the queue
+		return
+	}
+	<-done
+}
@@ -0,0 +1,99 @@
+package ringcache_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestPushAsync_AppliesInOrder(t *testing.T) {
+	var evicted []int
+	rc, _ := ringcache.NewWithEvictCallback[int, int](2, func(k, _ int) { evicted = append(evicted, k) },
+		ringcache.WithAsyncPush[int, int](4))
+	defer rc.Close()
+
+	for i := 1; i <= 5; i++ {
+		if err := rc.PushAsync(i, i); err != nil {
+			t.Fatalf("PushAsync(%d): %v", i, err)
+		}
+	}
+	rc.PushAsync(4, 40) // update of the oldest key, which takes the newest position
+	if err := rc.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if !slices.Equal(evicted, []int{1, 2, 3}) {
+		t.Fatalf("evicted = %v, want [1 2 3]", evicted)
+	}
+	if v, ok := rc.Load(4); !ok || v != 40 {
+		t.Fatalf("Load(4) = %d, %v; want 40, true", v, ok)
+	}
+	if keys, _ := rc.RingOrder(); !slices.Equal(keys, []int{5, 4}) {
+		t.Fatalf("ring order = %v, want [5 4]", keys)
+	}
+}
+
+func TestPushAsync_Close(t *testing.T) {
+	rc, _ := ringcache.New[int, int](8, ringcache.WithAsyncPush[int, int](8))
+	for i := 0; i < 8; i++ {
+		rc.PushAsync(i, i)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if rc.Size() != 8 {
+		t.Fatalf("size = %d after Close, want the queued pushes applied", rc.Size())
+	}
+	if err := rc.PushAsync(9, 9); !errors.Is(err, ringcache.ErrClosed) {
+		t.Fatalf("PushAsync after Close = %v, want ErrClosed", err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if err := rc.Flush(); err != nil {
+		t.Fatalf("Flush after Close: %v", err)
+	}
+	rc.Push(9, 9) // synchronous pushes keep working
+	if !rc.Has(9) {
+		t.Fatal("Push after Close not applied")
+	}
+}
+
+func TestPushAsync_WithoutOption(t *testing.T) {
+	rc, _ := ringcache.New[int, int](2)
+	if err := rc.PushAsync(1, 1); err != nil || !rc.Has(1) {
+		t.Fatalf("PushAsync without WithAsyncPush must push synchronously: %v", err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := ringcache.New[int, int](2, ringcache.WithAsyncPush[int, int](-1)); err == nil {
+		t.Fatal("negative queue size accepted")
+	}
+}
+
+func TestPushAsync_Concurrent(t *testing.T) {
+	rc, _ := ringcache.New[int, int](1024, ringcache.WithAsyncPush[int, int](16))
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				rc.PushAsync(w*1000+i, i)
+				rc.Load(w*1000 + i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	rc.Flush()
+	if rc.Size() != 800 {
+		t.Fatalf("size = %d, want 800", rc.Size())
+	}
+	rc.Close()
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,278 @@
+package ringcache_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/chi07/ringcache"
+	"github.com/chi07/ringcache/lru"
+)
+
+// benchCache is the subset of operations shared by RingCache and the lru.Cache baseline.
+type benchCache interface {
+	Push(key int, value int) bool
+	Load(key int) (int, bool)
+}
+
+var (
+	benchCapacities = []int{1 << 10, 1 << 16}
+	benchReadRatios = []int{10, 50, 90} // percentage of Loads in the mixed workload
+)
+
+// benchImpls returns constructors for every implementation under comparison.
+func benchImpls() []struct {
+	name string
+	new  func(capacity int) benchCache
+} {
+	return []struct {
+		name string
+		new  func(capacity int) benchCache
+	}{
+		{"ring", func(capacity int) benchCache {
+			rc, _ := ringcache.New[int, int](capacity)
+			return rc
+		}},
+		{"lru", func(capacity int) benchCache {
+			lc, _ := lru.New[int, int](capacity, nil)
+			return lc
+		}},
+	}
+}
+
+// benchKeys returns n keys drawn from [0, 2*capacity), so roughly half of the Loads hit.
+func benchKeys(n, capacity int) []int {
+	r := rand.New(rand.NewPCG(1, 2))
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = r.IntN(2 * capacity)
+	}
+	return keys
+}
+
+// fill pushes capacity distinct keys so benchmarks start from a full cache.
+func fill(c benchCache, capacity int) {
+	for i := 0; i < capacity; i++ {
+		c.Push(i, i)
+	}
+}
+
+func BenchmarkPush(b *testing.B) {
+	for _, impl := range benchImpls() {
+		for _, capacity := range benchCapacities {
+			b.Run(fmt.Sprintf("%s/cap=%d", impl.name, capacity), func(b *testing.B) {
+				c := impl.new(capacity)
+				fill(c, capacity)
+				keys := benchKeys(1<<16, capacity)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					k := keys[i&(len(keys)-1)]
+					c.Push(k, i)
+				}
+			})
+			// Only distinct keys into a full cache: every Push evicts, which the mixed keys
+			// above average away with in-place updates.
+			b.Run(fmt.Sprintf("%s/cap=%d/evicting", impl.name, capacity), func(b *testing.B) {
+				c := impl.new(capacity)
+				fill(c, capacity)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					c.Push(capacity+i, i)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkPushEvictTTL pushes distinct keys into a full cache whose entries all carry a TTL
+// that has not passed, so every Push evicts and must not scan the ring for expired slots.
+func BenchmarkPushEvictTTL(b *testing.B) {
+	for _, capacity := range benchCapacities {
+		b.Run(fmt.Sprintf("cap=%d", capacity), func(b *testing.B) {
+			rc, _ := ringcache.NewWithTTL[int, int](capacity, time.Hour)
+			fill(rc, capacity)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rc.Push(capacity+i, i)
+			}
+		})
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	for _, impl := range benchImpls() {
+		for _, capacity := range benchCapacities {
+			b.Run(fmt.Sprintf("%s/cap=%d", impl.name, capacity), func(b *testing.B) {
+				c := impl.new(capacity)
+				fill(c, capacity)
+				keys := benchKeys(1<<16, capacity)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					c.Load(keys[i&(len(keys)-1)])
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkMixed(b *testing.B) {
+	for _, impl := range benchImpls() {
+		for _, capacity := range benchCapacities {
+			for _, reads := range benchReadRatios {
+				name := fmt.Sprintf("%s/cap=%d/reads=%d%%", impl.name, capacity, reads)
+				b.Run(name, func(b *testing.B) {
+					c := impl.new(capacity)
+					fill(c, capacity)
+					keys := benchKeys(1<<16, capacity)
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						k := keys[i&(len(keys)-1)]
+						if k%100 < reads {
+							c.Load(k)
+						} else {
+							c.Push(k, i)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkMixedParallel(b *testing.B) {
+	for _, impl := range benchImpls() {
+		for _, reads := range benchReadRatios {
+			capacity := benchCapacities[0]
+			name := fmt.Sprintf("%s/cap=%d/reads=%d%%", impl.name, capacity, reads)
+			b.Run(name, func(b *testing.B) {
+				c := impl.new(capacity)
+				fill(c, capacity)
+				keys := benchKeys(1<<16, capacity)
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					i := rand.IntN(len(keys))
+					for pb.Next() {
+						k := keys[i&(len(keys)-1)]
+						if k%100 < reads {
+							c.Load(k)
+						} else {
+							c.Push(k, i)
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
+
+// bigValue is large enough that copying it would be noticeable; Load must still not allocate.
+type bigValue struct {
+	payload [512]byte
+}
+
+func TestLoad_ZeroAllocs(t *testing.T) {
+	rc, _ := ringcache.New[int, bigValue](16)
+	for i := 0; i < 16; i++ {
+		rc.Push(i, bigValue{})
+	}
+	allocs := testing.AllocsPerRun(1000, func() {
+		_, _ = rc.Load(3)  // hit
+		_, _ = rc.Load(99) // miss
+	})
+	if allocs != 0 {
+		t.Fatalf("Load allocated %.1f times per run, want 0", allocs)
+	}
+}
+
+// An evicting Push recycles its victim slice, with or without an eviction callback.
+func TestPush_EvictingZeroAllocs(t *testing.T) {
+	plain, _ := ringcache.New[int, int](16)
+	withCB, _ := ringcache.NewWithEvictCallback[int, int](16, func(int, int) {})
+	for _, rc := range []*ringcache.RingCache[int, int]{plain, withCB} {
+		for i := 0; i < 16; i++ {
+			rc.Push(i, i)
+		}
+		k := 16
+		allocs := testing.AllocsPerRun(1000, func() {
+			if !rc.Push(k, k) {
+				t.Fatalf("Push(%d) into a full cache did not evict", k)
+			}
+			k++
+		})
+		if allocs != 0 {
+			t.Fatalf("evicting Push allocated %.1f times per run, want 0", allocs)
+		}
+	}
+}
+
+func BenchmarkLoadLargeValue(b *testing.B) {
+	rc, _ := ringcache.New[int, bigValue](1024)
+	for i := 0; i < 1024; i++ {
+		rc.Push(i, bigValue{})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = rc.Load(i & 1023)
+	}
+}
+
+// BenchmarkLoadLargeValuePointer is BenchmarkLoadLargeValue with V = *bigValue: Load copies a
+// pointer instead of 512 bytes.
+func BenchmarkLoadLargeValuePointer(b *testing.B) {
+	rc, _ := ringcache.New[int, *bigValue](1024)
+	for i := 0; i < 1024; i++ {
+		rc.Push(i, &bigValue{})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = rc.Load(i & 1023)
+	}
+}
+
+// BenchmarkPushLargeValue compares storing large values inline with storing pointers, which
+// costs the caller one allocation per Push but keeps the map entries small.
+func BenchmarkPushLargeValue(b *testing.B) {
+	b.Run("value", func(b *testing.B) {
+		rc, _ := ringcache.New[int, bigValue](1024)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v bigValue
+			v.payload[0] = byte(i)
+			rc.Push(i&4095, v)
+		}
+	})
+	b.Run("pointer", func(b *testing.B) {
+		rc, _ := ringcache.New[int, *bigValue](1024)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			v := &bigValue{}
+			v.payload[0] = byte(i)
+			rc.Push(i&4095, v)
+		}
+	})
+}
+
+// BenchmarkClear measures a churn of small fills and Clears on a large ring with an eviction
+// callback, where reusing the internal maps and the eviction buffer matters most.
+func BenchmarkClear(b *testing.B) {
+	var evicted int
+	rc, _ := ringcache.NewWithEvictCallback[int, int](1024, func(int, int) { evicted++ })
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for k := 0; k < 64; k++ {
+			rc.Push(k, i)
+		}
+		rc.Clear()
+	}
+}
@@ -0,0 +1,125 @@
+package ringcache
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Binary stream layout: a 4-byte magic, one version byte, then a gob-encoded payload.
+// Version 2 added per-entry expiries; ReadBinary still accepts version 1 streams.
+const (
+	binaryMagic   = "RCBN"
+	binaryVersion = 2
+)
+
+var (
+	// ErrInvalidBinary is returned by ReadBinary when the stream does not start with
+	// the RingCache binary header.
+	ErrInvalidBinary = errors.New("ringcache: not a ringcache binary stream")
+
+	// ErrUnsupportedVersion is returned by ReadBinary when the stream was written by
+	// an incompatible version of the binary format.
+	ErrUnsupportedVersion = errors.New("ringcache: unsupported binary format version")
+)
+
+// binaryPayload is the gob-encoded body of the binary format.
+// Keys, Values and Expires are parallel slices in ring order, oldest first. A zero expiry means
+// the entry never expires; Expires is nil in version 1 streams.
+type binaryPayload[K comparable, V any] struct {
+	Capacity int
+	Count    int
+	Keys     []K
+	Values   []V
+	Expires  []time.Time
+}
+
+// WriteBinary writes the cache contents to w in a versioned binary format:
+// a header identifying the format and its version, followed by the capacity,
+// the entry count and every key/value pair (oldest first) with its expiry, if any, encoded with
+// encoding/gob. Expired entries not removed yet are left out. K and V must be encodable by gob.
+func (c *RingCache[K, V]) WriteBinary(w io.Writer) error {
+	p := binaryPayload[K, V]{}
+	c.mu.RLock()
+	p.Capacity = c.capacity
+	c.forEachLive(func(k K) {
+		p.Keys = append(p.Keys, k)
+		p.Values = append(p.Values, c.items[k])
+		p.Expires = append(p.Expires, c.expires[k])
+	})
+	c.mu.RUnlock()
+	p.Count = len(p.Keys)
+
+	if _, err := w.Write([]byte{binaryMagic[0], binaryMagic[1], binaryMagic[2], binaryMagic[3], binaryVersion}); err != nil {
+		return fmt.Errorf("ringcache: write header: %w", err)
+	}
+	if err := gob.NewEncoder(w).Encode(p); err != nil {
+		return fmt.Errorf("ringcache: encode entries: %w", err)
+	}
+	return nil
+}
+
+// ReadBinary replaces the cache contents with entries read from a stream produced by WriteBinary.
+// Streams with a foreign header or another format version are rejected with
+// ErrInvalidBinary or ErrUnsupportedVersion; a frozen cache returns ErrFrozen.
+// The whole stream is decoded before the cache is touched, so on error the cache is left unchanged.
+//
+// Entries are restored in their original order with their expiries; entries that have expired
+// by the time of the read are dropped. Entries from version 1 streams, which carry no expiries,
+// get the WithDefaultTTL expiry if one is set. If the stream holds more entries than
+// Capacity(), only the newest Capacity() are kept. Replaced and dropped entries do not
+// trigger the eviction callback, and pins are cleared.
+func (c *RingCache[K, V]) ReadBinary(r io.Reader) error {
+	var header [len(binaryMagic) + 1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return fmt.Errorf("ringcache: read header: %w", err)
+	}
+	if string(header[:len(binaryMagic)]) != binaryMagic {
+		return ErrInvalidBinary
+	}
+	if v := header[len(binaryMagic)]; v != 1 && v != binaryVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, v)
+	}
+
+	var p binaryPayload[K, V]
+	if err := gob.NewDecoder(r).Decode(&p); err != nil {
+		return fmt.Errorf("ringcache: decode entries: %w", err)
+	}
+	if len(p.Keys) != p.Count || len(p.Values) != p.Count || (p.Expires != nil && len(p.Expires) != p.Count) {
+		return fmt.Errorf("ringcache: decode entries: count %d does not match %d keys, %d values and %d expiries",
+			p.Count, len(p.Keys), len(p.Values), len(p.Expires))
+	}
+
+	var edge func()
+	defer func() { runHook(edge) }() // runs after the unlock below
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	c.reset()
+	c.gen.Add(1)
+	now := c.now()
+	for i := range p.Keys {
+		if p.Expires == nil {
+			c.push(nil, p.Keys[i], p.Values[i])
+			continue
+		}
+		exp := p.Expires[i]
+		if !exp.IsZero() && !now.Before(exp) {
+			continue
+		}
+		if _, stored := c.push(nil, p.Keys[i], p.Values[i]); !stored {
+			continue
+		}
+		if exp.IsZero() {
+			delete(c.expires, p.Keys[i])
+		} else {
+			c.setExpiryAt(p.Keys[i], exp)
+		}
+	}
+	edge = c.fullEdge()
+	return nil
+}
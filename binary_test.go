@@ -0,0 +1,92 @@
+package ringcache_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestBinary_RoundTrip(t *testing.T) {
+	src, _ := ringcache.New[int, string](3)
+	src.Push(1, "one")
+	src.Push(2, "two")
+	src.Push(3, "three")
+	src.Push(4, "four") // evicts 1
+
+	var buf bytes.Buffer
+	if err := src.WriteBinary(&buf); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+
+	dst, _ := ringcache.New[int, string](3)
+	dst.Push(99, "stale")
+	if err := dst.ReadBinary(&buf); err != nil {
+		t.Fatalf("ReadBinary: %v", err)
+	}
+
+	if dst.Size() != 3 || dst.Has(99) || dst.Has(1) {
+		t.Fatalf("restored contents mismatch: size=%d", dst.Size())
+	}
+	for k, want := range map[int]string{2: "two", 3: "three", 4: "four"} {
+		if v, ok := dst.Load(k); !ok || v != want {
+			t.Fatalf("Load(%d) = (%q,%v), want (%q,true)", k, v, ok, want)
+		}
+	}
+
+	// Ring order is preserved: the oldest restored entry is evicted first.
+	dst.Push(5, "five")
+	if dst.Has(2) {
+		t.Fatalf("2 should be evicted first after restore")
+	}
+}
+
+func TestBinary_SmallerCapacityKeepsNewest(t *testing.T) {
+	src, _ := ringcache.New[int, string](3)
+	src.Push(1, "one")
+	src.Push(2, "two")
+	src.Push(3, "three")
+
+	var buf bytes.Buffer
+	if err := src.WriteBinary(&buf); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+
+	var evicted int
+	dst, _ := ringcache.NewWithEvictCallback[int, string](2, func(int, string) { evicted++ })
+	if err := dst.ReadBinary(&buf); err != nil {
+		t.Fatalf("ReadBinary: %v", err)
+	}
+	if dst.Has(1) || !dst.Has(2) || !dst.Has(3) {
+		t.Fatalf("expected only the newest two entries to be restored")
+	}
+	if evicted != 0 {
+		t.Fatalf("restore should not fire the eviction callback, got %d calls", evicted)
+	}
+}
+
+func TestBinary_RejectsBadHeader(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	rc.Push(1, "one")
+
+	err := rc.ReadBinary(bytes.NewReader([]byte("nope!")))
+	if !errors.Is(err, ringcache.ErrInvalidBinary) {
+		t.Fatalf("expected ErrInvalidBinary, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := rc.WriteBinary(&buf); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	data := buf.Bytes()
+	data[4] = 99 // bump the version byte
+	err = rc.ReadBinary(bytes.NewReader(data))
+	if !errors.Is(err, ringcache.ErrUnsupportedVersion) {
+		t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+	}
+
+	if v, ok := rc.Load(1); !ok || v != "one" {
+		t.Fatalf("failed ReadBinary must leave the cache unchanged")
+	}
+}
@@ -0,0 +1,49 @@
+package ringcache
+
+import "iter"
+
+// Cache is the core method set shared by bounded key/value caches, so code can depend on the
+// behavior rather than on *RingCache: inject a mock in tests, or switch between RingCache and
+// the lru subpackage. It covers the everyday operations only; ring-specific features (pins,
+// slots, Swap, ...) stay on the concrete type. Methods join the interface as generally
+// applicable features are added.
+//
+// The listing methods (Keys, Values, Entries, Range, All) yield entries oldest first: in ring
+// order from the next write position for RingCache (oldest Push first), least recently used
+// first for lru. That is the order plain evictions follow, but it does not predict every
+// eviction: RingCache skips pinned keys, evicts by score under WithScoreEvict and reclaims
+// expired slots first.
+type Cache[K comparable, V any] interface {
+	// Push stores key with value as the newest entry and reports whether an entry was evicted.
+	Push(key K, value V) (evicted bool)
+	// Load returns the value for key and whether it was present.
+	Load(key K) (V, bool)
+	// LoadOrDefault returns the value for key, or def if absent, without storing def.
+	LoadOrDefault(key K, def V) V
+	// Has reports whether key is present.
+	Has(key K) bool
+	// Delete removes key and reports whether it was present.
+	Delete(key K) bool
+	// Clear removes every entry.
+	Clear()
+	// Size returns the number of entries.
+	Size() int
+	// Capacity returns the maximum number of entries.
+	Capacity() int
+	// Peek returns the value for key like Load, without counting as a use or any other side
+	// effect.
+	Peek(key K) (V, bool)
+	// Keys returns the keys oldest first as a fresh slice.
+	Keys() []K
+	// Values returns the values in the order of Keys as a fresh slice.
+	Values() []V
+	// Entries returns the key/value pairs in the order of Keys, read consistently.
+	Entries() []Entry[K, V]
+	// Range calls fn for every entry in the order of Keys until fn returns false. fn must not
+	// call the cache.
+	Range(fn func(key K, value V) bool)
+	// All returns an iterator over a snapshot of the entries in the order of Keys.
+	All() iter.Seq2[K, V]
+}
+
+var _ Cache[string, int] = (*RingCache[string, int])(nil)
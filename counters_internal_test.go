@@ -0,0 +1,31 @@
+package ringcache
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEvictionAge_WrapSafe(t *testing.T) {
+	c, _ := New[int, string](2, WithEvictionAgeHistogram[int, string]())
+	c.seq = math.MaxUint64 - 1 // the next Push takes the last value before the wrap
+	c.Push(1, "a")             // seq MaxUint64
+	c.Push(2, "b")             // seq 0 (wrapped)
+	c.Push(3, "c")             // seq 1, evicts 1 at age 2
+
+	if s, _ := c.Sequence(1); s != 0 {
+		t.Fatalf("evicted key still has sequence %d", s)
+	}
+	if got := c.EvictionAgeHistogram(); got[1] != 1 {
+		t.Fatalf("age 2 should land in bucket 1 across the wrap, histogram %v", got[:4])
+	}
+}
+
+func TestGeneration_WrapsToZero(t *testing.T) {
+	c, _ := New[int, string](1)
+	c.gen.Store(math.MaxUint64)
+	before := c.Generation()
+	c.Push(1, "a")
+	if after := c.Generation(); after == before {
+		t.Fatalf("generation must change across the wrap, stayed %d", after)
+	}
+}
@@ -0,0 +1,198 @@
+package ringcache
+
+import "fmt"
+
+// Healthy checks the internal invariants of the cache and returns an error describing the
+// first violation found, or nil. In normal operation it always returns nil; it is a safety net
+// for production monitoring that would surface an internal bug.
+//
+// Checked invariants: next is a valid slot; the Size counter matches the number of items;
+// every key in the value map has a position whose slot is occupied by that key; no two keys
+// share a slot; the number of occupied slots equals Size(); every cached key has a version;
+// and pinned keys, sequence numbers, insertion times and expiries only exist for cached keys.
+// It takes the read lock and runs in O(Capacity()).
+func (c *RingCache[K, V]) Healthy() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.next < 0 || (c.next >= c.capacity && c.capacity > 0) {
+		return fmt.Errorf("ringcache: next index %d out of range [0, %d)", c.next, c.capacity)
+	}
+	if n := c.size.Load(); n != int64(len(c.items)) {
+		return fmt.Errorf("ringcache: size counter %d for %d items", n, len(c.items))
+	}
+	if len(c.pos) != len(c.items) {
+		return fmt.Errorf("ringcache: %d positions for %d items", len(c.pos), len(c.items))
+	}
+	for k := range c.items {
+		p, ok := c.pos[k]
+		if !ok {
+			return fmt.Errorf("ringcache: key %v has no slot", k)
+		}
+		if p < 0 || p >= c.capacity {
+			return fmt.Errorf("ringcache: key %v has slot %d out of range", k, p)
+		}
+		if !c.occupied[p] || c.keys[p] != k {
+			return fmt.Errorf("ringcache: slot %d does not hold key %v", p, k)
+		}
+	}
+	// Each item maps to its own occupied slot, so any extra occupied slot is a stale duplicate.
+	occupied := 0
+	for _, o := range c.occupied {
+		if o {
+			occupied++
+		}
+	}
+	if occupied != len(c.items) {
+		return fmt.Errorf("ringcache: %d occupied slots for %d items", occupied, len(c.items))
+	}
+	for k := range c.pinned {
+		if _, ok := c.items[k]; !ok {
+			return fmt.Errorf("ringcache: pinned key %v is not cached", k)
+		}
+	}
+	if len(c.versions) != len(c.items) {
+		return fmt.Errorf("ringcache: %d versions for %d items", len(c.versions), len(c.items))
+	}
+	if c.seqs != nil && len(c.seqs) != len(c.items) {
+		return fmt.Errorf("ringcache: %d sequence numbers for %d items", len(c.seqs), len(c.items))
+	}
+	if c.inserted != nil && len(c.inserted) != len(c.items) {
+		return fmt.Errorf("ringcache: %d insertion times for %d items", len(c.inserted), len(c.items))
+	}
+	for k := range c.expires {
+		if _, ok := c.items[k]; !ok {
+			return fmt.Errorf("ringcache: expiry kept for key %v which is not cached", k)
+		}
+	}
+	return nil
+}
+
+// LoadDebug is a debug-only Load for chasing suspected corruption between the value map and the
+// slot bookkeeping. Under one read lock it returns key's value and slot, and whether they agree:
+// consistent is true only if key has a valid slot that is marked occupied and holds key. ok
+// reports whether key has a live value; slot is -1 if key has no slot. An expired entry not
+// removed yet yields a zero value and ok false, while slot and consistent still describe its
+// bookkeeping. It does not count in Stats. Outside of debugging use Load, or Healthy for a
+// whole-cache check.
+func (c *RingCache[K, V]) LoadDebug(key K) (value V, slot int, consistent bool, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok = c.items[key]
+	p, hasPos := c.pos[key]
+	if hasPos {
+		slot = p
+		consistent = ok && p >= 0 && p < c.capacity && c.occupied[p] && c.keys[p] == key
+	} else {
+		slot = -1
+	}
+	if ok && c.isExpired(key) {
+		var zero V
+		value, ok = zero, false
+	}
+	return value, slot, consistent, ok
+}
+
+// Verify is the exhaustive counterpart of Healthy: instead of stopping at the first problem it
+// checks every invariant and returns one error per inconsistency found, or nil if there is
+// none. Besides Healthy's checks it reports each offending key or slot individually: items
+// without a valid slot, positions without an item (orphans), keys sharing a slot, occupied
+// slots whose key is not cached or maps elsewhere, and per-key state (pins, versions, sequence
+// numbers, insertion times, expiries) for keys that are not cached or missing for keys that
+// are. Slot problems are listed in slot order, key problems in no particular order. It takes
+// the read lock and runs in O(Capacity() + Size()), allocating as it goes, so it is meant for
+// tests and diagnosis.
+func (c *RingCache[K, V]) Verify() []error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var errs []error
+	report := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("ringcache: "+format, args...))
+	}
+
+	if c.next < 0 || (c.next >= c.capacity && c.capacity > 0) {
+		report("next index %d out of range [0, %d)", c.next, c.capacity)
+	}
+	if n := c.size.Load(); n != int64(len(c.items)) {
+		report("size counter %d for %d items", n, len(c.items))
+	}
+
+	for i, occ := range c.occupied {
+		if !occ {
+			continue
+		}
+		k := c.keys[i]
+		if _, ok := c.items[k]; !ok {
+			report("occupied slot %d holds key %v which is not cached", i, k)
+		} else if p, ok := c.pos[k]; ok && p != i {
+			report("occupied slot %d holds key %v whose position is %d", i, k, p)
+		}
+	}
+
+	owner := make(map[int]K, len(c.pos))
+	for k, p := range c.pos {
+		if _, ok := c.items[k]; !ok {
+			report("orphaned position %d for key %v which is not cached", p, k)
+		}
+		if p < 0 || p >= c.capacity {
+			report("key %v has slot %d out of range", k, p)
+			continue
+		}
+		if other, dup := owner[p]; dup {
+			report("keys %v and %v share slot %d", other, k, p)
+		} else {
+			owner[p] = k
+		}
+		if !c.occupied[p] {
+			report("key %v maps to free slot %d", k, p)
+		} else if c.keys[p] != k {
+			report("key %v maps to slot %d holding key %v", k, p, c.keys[p])
+		}
+	}
+	for k := range c.items {
+		if _, ok := c.pos[k]; !ok {
+			report("key %v has no slot", k)
+		}
+		if _, ok := c.versions[k]; !ok {
+			report("key %v has no version", k)
+		}
+		if c.seqs != nil {
+			if _, ok := c.seqs[k]; !ok {
+				report("key %v has no sequence number", k)
+			}
+		}
+		if c.inserted != nil {
+			if _, ok := c.inserted[k]; !ok {
+				report("key %v has no insertion time", k)
+			}
+		}
+	}
+
+	for k := range c.pinned {
+		if _, ok := c.items[k]; !ok {
+			report("pinned key %v is not cached", k)
+		}
+	}
+	for k := range c.versions {
+		if _, ok := c.items[k]; !ok {
+			report("version kept for key %v which is not cached", k)
+		}
+	}
+	for k := range c.seqs {
+		if _, ok := c.items[k]; !ok {
+			report("sequence number kept for key %v which is not cached", k)
+		}
+	}
+	for k := range c.inserted {
+		if _, ok := c.items[k]; !ok {
+			report("insertion time kept for key %v which is not cached", k)
+		}
+	}
+	for k := range c.expires {
+		if _, ok := c.items[k]; !ok {
+			report("expiry kept for key %v which is not cached", k)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,62 @@
+package ringcache
+
+import "testing"
+
+func TestHealthy_DetectsCorruption(t *testing.T) {
+	corruptions := map[string]func(c *RingCache[int, string]){
+		"next out of range": func(c *RingCache[int, string]) { c.next = c.capacity },
+		"size drift":        func(c *RingCache[int, string]) { c.size.Add(1) },
+		"missing position":  func(c *RingCache[int, string]) { delete(c.pos, 1) },
+		"wrong slot key":    func(c *RingCache[int, string]) { c.keys[c.pos[1]] = 42 },
+		"stale occupied":    func(c *RingCache[int, string]) { c.occupied[2] = true },
+		"orphan pin":        func(c *RingCache[int, string]) { c.pinned[42] = struct{}{} },
+	}
+	for name, corrupt := range corruptions {
+		t.Run(name, func(t *testing.T) {
+			c, _ := New[int, string](3)
+			c.Push(1, "one")
+			c.Push(2, "two")
+			if err := c.Healthy(); err != nil {
+				t.Fatalf("unexpected error before corruption: %v", err)
+			}
+			corrupt(c)
+			if err := c.Healthy(); err == nil {
+				t.Fatalf("corruption not detected")
+			}
+		})
+	}
+}
+
+func TestLoadDebug_DetectsMismatch(t *testing.T) {
+	c, _ := New[int, string](3)
+	c.Push(1, "one")
+	c.Push(2, "two")
+	c.keys[c.pos[1]] = 2 // slot of 1 claims to hold 2
+
+	v, slot, consistent, ok := c.LoadDebug(1)
+	if !ok || consistent || v != "one" || slot != 0 {
+		t.Fatalf("LoadDebug(1) = %q, %d, %v, %v; want one, 0, false, true", v, slot, consistent, ok)
+	}
+}
+
+func TestVerify_ReportsEveryProblem(t *testing.T) {
+	c, _ := New[int, string](4, WithSequence[int, string]())
+	c.Push(1, "one")
+	c.Push(2, "two")
+	c.Push(3, "three")
+	if errs := c.Verify(); errs != nil {
+		t.Fatalf("unexpected problems in a healthy cache: %v", errs)
+	}
+
+	c.pos[2] = c.pos[1]       // 1 and 2 share slot 0; slot 1 claims 2 mapped elsewhere
+	c.pinned[42] = struct{}{} // orphan pin
+	delete(c.seqs, 3)         // missing sequence number
+
+	errs := c.Verify()
+	if len(errs) != 5 {
+		t.Fatalf("got %d problems, want 5:\n%v", len(errs), errs)
+	}
+	if c.Healthy() == nil {
+		t.Fatalf("Healthy must also fail")
+	}
+}
@@ -0,0 +1,44 @@
+package ringcache_test
+
+import (
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestHealthy_AfterMixedOperations(t *testing.T) {
+	rc, _ := ringcache.New[int, int](5, ringcache.WithSequence[int, int]())
+	for i := 0; i < 100; i++ {
+		rc.Push(i%7, i)
+		if i%3 == 0 {
+			rc.Delete(i % 5)
+		}
+		if i%11 == 0 {
+			rc.Pin(i % 7)
+		}
+		if i%13 == 0 {
+			rc.Unpin(i % 7)
+		}
+		if err := rc.Healthy(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	rc.Clear()
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("after clear: %v", err)
+	}
+}
+
+func TestLoadDebug(t *testing.T) {
+	rc, _ := ringcache.New[string, int](3)
+	rc.Push("a", 1)
+	rc.Push("b", 2)
+
+	v, slot, consistent, ok := rc.LoadDebug("b")
+	if !ok || !consistent || v != 2 || slot != 1 {
+		t.Fatalf("LoadDebug(b) = %d, %d, %v, %v; want 2, 1, true, true", v, slot, consistent, ok)
+	}
+	if _, slot, consistent, ok := rc.LoadDebug("missing"); ok || consistent || slot != -1 {
+		t.Fatalf("LoadDebug(missing) = slot %d, consistent %v, ok %v", slot, consistent, ok)
+	}
+}
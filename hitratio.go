@@ -0,0 +1,43 @@
+package ringcache
+
+import "sync"
+
+// hitWatch implements WithLowHitRatioWarning.
+type hitWatch struct {
+	threshold float64
+	window    int
+	warn      func(ratio float64)
+
+	mu     sync.Mutex
+	ops    int  // Loads in the current window
+	hits   int  // hits in the current window
+	warned bool // a warning fired and the ratio has not recovered since
+}
+
+// observe records one Load and, when it completes a window whose hit ratio is below the
+// threshold, calls warn unless a warning is already outstanding.
+func (w *hitWatch) observe(hit bool) {
+	w.mu.Lock()
+	w.ops++
+	if hit {
+		w.hits++
+	}
+	if w.ops < w.window {
+		w.mu.Unlock()
+		return
+	}
+	ratio := float64(w.hits) / float64(w.ops)
+	w.ops, w.hits = 0, 0
+	fire := false
+	if ratio < w.threshold {
+		fire = !w.warned
+		w.warned = true
+	} else {
+		w.warned = false
+	}
+	w.mu.Unlock()
+
+	if fire {
+		w.warn(ratio)
+	}
+}
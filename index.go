@@ -0,0 +1,82 @@
+package ringcache
+
+// index is a secondary index mapping a derived attribute to the keys whose entries produce it.
+type index[K comparable, V any] struct {
+	extract func(K, V) string
+	attrs   map[string]map[K]struct{}
+}
+
+// WithIndex adds a secondary index called name over an attribute derived from each entry,
+// e.g. the user ID of a cached session, queried with ByIndex. extract must be deterministic:
+// the same key and value must always yield the same attribute.
+//
+// Indexes are maintained under the write lock on every insertion, update and removal
+// (including evictions and Clear), so each index adds one extract call and a couple of map
+// operations to every write, plus memory proportional to Size().
+func WithIndex[K comparable, V any](name string, extract func(key K, value V) string) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		if cfg.indexes == nil {
+			cfg.indexes = make(map[string]func(K, V) string)
+		}
+		cfg.indexes[name] = extract
+	}
+}
+
+// ByIndex returns the keys whose entries have the given attribute value in the named index,
+// in no particular order, leaving out expired entries not removed yet. It returns nil if there
+// are none or no such index exists.
+func (c *RingCache[K, V]) ByIndex(name, value string) []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ix, ok := c.indexes[name]
+	if !ok {
+		return nil
+	}
+	set := ix.attrs[value]
+	if len(set) == 0 {
+		return nil
+	}
+	var keys []K
+	for k := range set {
+		if !c.isExpired(k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// indexAdd records (key, value) in every index. Caller must hold c.mu.
+func (c *RingCache[K, V]) indexAdd(key K, value V) {
+	for _, ix := range c.indexes {
+		a := ix.extract(key, value)
+		set := ix.attrs[a]
+		if set == nil {
+			set = make(map[K]struct{})
+			ix.attrs[a] = set
+		}
+		set[key] = struct{}{}
+	}
+}
+
+// indexRemove drops (key, value) from every index. Caller must hold c.mu.
+func (c *RingCache[K, V]) indexRemove(key K, value V) {
+	for _, ix := range c.indexes {
+		a := ix.extract(key, value)
+		if set := ix.attrs[a]; set != nil {
+			delete(set, key)
+			if len(set) == 0 {
+				delete(ix.attrs, a)
+			}
+		}
+	}
+}
+
+// rebuildIndexes recomputes every index from the current entries. Caller must hold c.mu.
+func (c *RingCache[K, V]) rebuildIndexes() {
+	for _, ix := range c.indexes {
+		ix.attrs = make(map[string]map[K]struct{})
+	}
+	for k, v := range c.items {
+		c.indexAdd(k, v)
+	}
+}
@@ -0,0 +1,85 @@
+package ringcache_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+type session struct {
+	user string
+}
+
+func byUser(_ string, s session) string { return s.user }
+
+func sortedByIndex(rc *ringcache.RingCache[string, session], user string) []string {
+	keys := rc.ByIndex("user", user)
+	slices.Sort(keys)
+	return keys
+}
+
+func TestIndex_MaintainedAcrossMutations(t *testing.T) {
+	rc, err := ringcache.New[string, session](3, ringcache.WithIndex[string, session]("user", byUser))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rc.Push("s1", session{user: "alice"})
+	rc.Push("s2", session{user: "bob"})
+	rc.Push("s3", session{user: "alice"})
+
+	if got := sortedByIndex(rc, "alice"); !slices.Equal(got, []string{"s1", "s3"}) {
+		t.Fatalf("alice sessions = %v, want [s1 s3]", got)
+	}
+
+	rc.Push("s4", session{user: "bob"}) // evicts s1
+	if got := sortedByIndex(rc, "alice"); !slices.Equal(got, []string{"s3"}) {
+		t.Fatalf("after eviction alice sessions = %v, want [s3]", got)
+	}
+
+	if err := rc.SetValue("s3", session{user: "bob"}); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	if got := rc.ByIndex("user", "alice"); got != nil {
+		t.Fatalf("after update alice sessions = %v, want none", got)
+	}
+	if got := sortedByIndex(rc, "bob"); !slices.Equal(got, []string{"s2", "s3", "s4"}) {
+		t.Fatalf("bob sessions = %v, want [s2 s3 s4]", got)
+	}
+
+	rc.Push("s2", session{user: "carol"}) // re-push changes the attribute
+	rc.Delete("s4")
+	if got := sortedByIndex(rc, "bob"); !slices.Equal(got, []string{"s3"}) {
+		t.Fatalf("bob sessions = %v, want [s3]", got)
+	}
+
+	rc.Clear()
+	if got := rc.ByIndex("user", "carol"); got != nil {
+		t.Fatalf("index should be empty after Clear, got %v", got)
+	}
+}
+
+func TestIndex_UnknownAndInvalid(t *testing.T) {
+	rc, _ := ringcache.New[string, session](1)
+	rc.Push("s1", session{user: "alice"})
+	if got := rc.ByIndex("user", "alice"); got != nil {
+		t.Fatalf("unknown index should return nil, got %v", got)
+	}
+	if _, err := ringcache.New[string, session](1, ringcache.WithIndex[string, session]("user", nil)); err == nil {
+		t.Fatalf("expected error for nil extract function")
+	}
+}
+
+func TestIndex_RebuiltOnSwap(t *testing.T) {
+	indexed, _ := ringcache.New[string, session](2, ringcache.WithIndex[string, session]("user", byUser))
+	indexed.Push("old", session{user: "alice"})
+	plain, _ := ringcache.New[string, session](2)
+	plain.Push("new", session{user: "alice"})
+
+	if err := indexed.Swap(plain); err != nil {
+		t.Fatalf("Swap: %v", err)
+	}
+	if got := sortedByIndex(indexed, "alice"); !slices.Equal(got, []string{"new"}) {
+		t.Fatalf("index after swap = %v, want [new]", got)
+	}
+}
@@ -0,0 +1,20 @@
+package ringcache
+
+// Key2 is a composite key of two comparable parts, usable directly as the K of a RingCache:
+//
+//	rc, _ := ringcache.New[ringcache.Key2[string, int], Session](1024)
+//	rc.Push(ringcache.Key2[string, int]{A: tenant, B: userID}, s)
+//
+// Unlike concatenated "a:b" strings, the parts are compared field by field, so no choice of
+// delimiter can make two different keys collide.
+type Key2[A, B comparable] struct {
+	A A
+	B B
+}
+
+// Key3 is a composite key of three comparable parts; see Key2.
+type Key3[A, B, C comparable] struct {
+	A A
+	B B
+	C C
+}
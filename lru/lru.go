@@ -0,0 +1,231 @@
+// Package lru provides a least-recently-used cache implementing ringcache.Cache, so callers can
+// switch between ring (FIFO) and LRU eviction behind one interface, e.g. to A/B test them.
+//
+// Unlike RingCache, every Load counts as a use: it moves the entry to the most recently used
+// position, so the entry evicted when the cache is full is the one read or written longest ago.
+package lru
+
+import (
+	"container/list"
+	"errors"
+	"iter"
+	"sync"
+
+	"github.com/chi07/ringcache"
+)
+
+var _ ringcache.Cache[string, int] = (*Cache[string, int])(nil)
+
+// Cache is a fixed-capacity LRU cache. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // most recently used at the front; elements hold *ringcache.Entry
+	items    map[K]*list.Element
+	onEvict  ringcache.EvictCallback[K, V]
+}
+
+// New creates an LRU cache holding up to capacity (> 0) entries.
+// onEvict, if not nil, is called outside the lock for every entry evicted or removed, with the
+// same semantics as the RingCache eviction callback.
+func New[K comparable, V any](capacity int, onEvict ringcache.EvictCallback[K, V]) (*Cache[K, V], error) {
+	if capacity <= 0 {
+		return nil, errors.New("lru: capacity must be greater than zero")
+	}
+	return &Cache[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element, capacity),
+		onEvict:  onEvict,
+	}, nil
+}
+
+// Push stores value under key as the most recently used entry. Pushing an existing key
+// replaces its value without evicting. Otherwise, if the cache is full, the least recently used
+// entry is evicted first. Returns true if an entry was evicted.
+func (c *Cache[K, V]) Push(key K, value V) (evicted bool) {
+	var victim ringcache.Entry[K, V]
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*ringcache.Entry[K, V]).Value = value
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		return false
+	}
+	if c.order.Len() == c.capacity {
+		victim = *c.removeElement(c.order.Back())
+		evicted = true
+	}
+	c.items[key] = c.order.PushFront(&ringcache.Entry[K, V]{Key: key, Value: value})
+	c.mu.Unlock()
+
+	if evicted && c.onEvict != nil {
+		c.onEvict(victim.Key, victim.Value)
+	}
+	return evicted
+}
+
+// Load returns (value, true) if key is present, marking it most recently used; otherwise
+// (zero, false).
+func (c *Cache[K, V]) Load(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*ringcache.Entry[K, V]).Value, true
+}
+
+// LoadOrDefault returns the value for key, marking it most recently used, or def if key is
+// absent. It never stores def.
+func (c *Cache[K, V]) LoadOrDefault(key K, def V) V {
+	if v, ok := c.Load(key); ok {
+		return v
+	}
+	return def
+}
+
+// Has reports whether key is present. Unlike Load it does not count as a use.
+func (c *Cache[K, V]) Has(key K) bool {
+	c.mu.Lock()
+	_, ok := c.items[key]
+	c.mu.Unlock()
+	return ok
+}
+
+// Peek returns the value for key like Load, but does not count as a use: the entry keeps its
+// place in the eviction order.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return el.Value.(*ringcache.Entry[K, V]).Value, true
+}
+
+// Delete removes key and returns true if it was present, invoking the eviction callback
+// (outside the lock).
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	el, ok := c.items[key]
+	if !ok {
+		c.mu.Unlock()
+		return false
+	}
+	e := c.removeElement(el)
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		c.onEvict(e.Key, e.Value)
+	}
+	return true
+}
+
+// Clear removes all entries, invoking the eviction callback for each (outside the lock),
+// least recently used first.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	old := c.order
+	c.order = list.New()
+	c.items = make(map[K]*list.Element, c.capacity)
+	c.mu.Unlock()
+
+	if c.onEvict == nil {
+		return
+	}
+	for el := old.Back(); el != nil; el = el.Prev() {
+		e := el.Value.(*ringcache.Entry[K, V])
+		c.onEvict(e.Key, e.Value)
+	}
+}
+
+// Size returns the number of entries in the cache.
+func (c *Cache[K, V]) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Capacity returns the maximum number of entries the cache holds.
+func (c *Cache[K, V]) Capacity() int {
+	return c.capacity
+}
+
+// Keys returns the cached keys least recently used (next to be evicted) first, as a fresh
+// slice. Listing the cache does not count as a use.
+func (c *Cache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]K, 0, c.order.Len())
+	for el := c.order.Back(); el != nil; el = el.Prev() {
+		keys = append(keys, el.Value.(*ringcache.Entry[K, V]).Key)
+	}
+	return keys
+}
+
+// Values returns the cached values in the order of Keys, as a fresh slice. Use Entries for a
+// consistent pairing with the keys.
+func (c *Cache[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values := make([]V, 0, c.order.Len())
+	for el := c.order.Back(); el != nil; el = el.Prev() {
+		values = append(values, el.Value.(*ringcache.Entry[K, V]).Value)
+	}
+	return values
+}
+
+// Entries returns the cached key/value pairs in the order of Keys, taken under a single lock.
+func (c *Cache[K, V]) Entries() []ringcache.Entry[K, V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries()
+}
+
+// Range calls fn for every entry in the order of Keys and stops early when fn returns false.
+// It holds the lock for the whole walk, so fn must be quick and must not call the cache; use
+// All or Entries when it needs to.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.order.Back(); el != nil; el = el.Prev() {
+		e := el.Value.(*ringcache.Entry[K, V])
+		if !fn(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the entries in the order of Keys. Each iteration ranges over a
+// snapshot taken when it starts, so the loop body may call the cache.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, e := range c.Entries() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// entries returns a copy of the entries, least recently used first. Caller must hold c.mu.
+func (c *Cache[K, V]) entries() []ringcache.Entry[K, V] {
+	out := make([]ringcache.Entry[K, V], 0, c.order.Len())
+	for el := c.order.Back(); el != nil; el = el.Prev() {
+		out = append(out, *el.Value.(*ringcache.Entry[K, V]))
+	}
+	return out
+}
+
+// removeElement unlinks el and drops its key. Caller must hold c.mu.
+func (c *Cache[K, V]) removeElement(el *list.Element) *ringcache.Entry[K, V] {
+	e := c.order.Remove(el).(*ringcache.Entry[K, V])
+	delete(c.items, e.Key)
+	return e
+}
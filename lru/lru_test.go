@@ -0,0 +1,148 @@
+package lru_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/chi07/ringcache"
+	"github.com/chi07/ringcache/lru"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	var evicted []int
+	c, err := lru.New[int, string](2, func(k int, _ string) { evicted = append(evicted, k) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Push(1, "one")
+	c.Push(2, "two")
+	c.Load(1) // 2 is now the least recently used
+
+	if !c.Push(3, "three") {
+		t.Fatalf("Push into a full cache should evict")
+	}
+	if fmt.Sprint(evicted) != "[2]" || c.Has(2) || !c.Has(1) {
+		t.Fatalf("evicted %v; want [2] with 1 kept", evicted)
+	}
+
+	// Has is not a use, so 1 stays older than 3.
+	c.Has(1)
+	c.Push(4, "four")
+	if fmt.Sprint(evicted) != "[2 1]" {
+		t.Fatalf("evicted %v, want [2 1]", evicted)
+	}
+}
+
+func TestLRU_UpdateDeleteClear(t *testing.T) {
+	var evicted []int
+	c, _ := lru.New[int, string](3, func(k int, _ string) { evicted = append(evicted, k) })
+	c.Push(1, "one")
+	if c.Push(1, "uno") {
+		t.Fatalf("updating an existing key must not evict")
+	}
+	if v, _ := c.Load(1); v != "uno" {
+		t.Fatalf("Load(1) = %q, want uno", v)
+	}
+	if got := c.LoadOrDefault(9, "dflt"); got != "dflt" || c.Has(9) {
+		t.Fatalf("LoadOrDefault(9) = %q; must not store", got)
+	}
+	c.Push(2, "two")
+	c.Push(3, "three")
+	if !c.Delete(2) || c.Delete(2) {
+		t.Fatalf("Delete should report presence once")
+	}
+	c.Clear()
+	if fmt.Sprint(evicted) != "[2 1 3]" || c.Size() != 0 {
+		t.Fatalf("evicted %v, size %d; want [2 1 3], 0", evicted, c.Size())
+	}
+	if c.Capacity() != 3 {
+		t.Fatalf("Capacity() = %d, want 3", c.Capacity())
+	}
+}
+
+func TestLRU_PeekAndListings(t *testing.T) {
+	c, _ := lru.New[int, string](3, nil)
+	c.Push(1, "one")
+	c.Push(2, "two")
+	c.Push(3, "three")
+	c.Load(1) // order is now 2, 3, 1
+
+	if v, ok := c.Peek(2); !ok || v != "two" {
+		t.Fatalf("Peek(2) = (%q, %v), want (\"two\", true)", v, ok)
+	}
+	if _, ok := c.Peek(9); ok {
+		t.Fatalf("Peek of an absent key should miss")
+	}
+	if got := fmt.Sprint(c.Keys()); got != "[2 3 1]" {
+		t.Fatalf("Keys() = %s, want [2 3 1]: Peek must not count as a use", got)
+	}
+	if got := fmt.Sprint(c.Values()); got != "[two three one]" {
+		t.Fatalf("Values() = %s, want [two three one]", got)
+	}
+	if got := fmt.Sprint(c.Entries()); got != "[{2 two} {3 three} {1 one}]" {
+		t.Fatalf("Entries() = %s", got)
+	}
+	var ranged []int
+	c.Range(func(k int, _ string) bool {
+		ranged = append(ranged, k)
+		return len(ranged) < 2
+	})
+	if fmt.Sprint(ranged) != "[2 3]" {
+		t.Fatalf("Range stopped after %v, want [2 3]", ranged)
+	}
+	var all []int
+	for k := range c.All() {
+		all = append(all, k)
+		c.Load(k) // the loop body may call the cache
+	}
+	if fmt.Sprint(all) != "[2 3 1]" {
+		t.Fatalf("All() yielded %v, want [2 3 1]", all)
+	}
+
+	// The listings do not count as a use either: 2 is still evicted first.
+	c.Keys()
+	c.Push(4, "four")
+	if c.Has(2) {
+		t.Fatalf("listing the cache must not refresh entries")
+	}
+}
+
+func TestLRU_Invalid(t *testing.T) {
+	if _, err := lru.New[int, int](0, nil); err == nil {
+		t.Fatalf("expected error for zero capacity")
+	}
+}
+
+// Both implementations are interchangeable behind ringcache.Cache.
+func TestLRU_SatisfiesCacheInterface(t *testing.T) {
+	ring, _ := ringcache.New[int, int](2)
+	lc, _ := lru.New[int, int](2, nil)
+	for _, c := range []ringcache.Cache[int, int]{ring, lc} {
+		c.Push(1, 1)
+		c.Push(2, 2)
+		c.Push(3, 3)
+		if c.Size() != 2 || c.Has(1) {
+			t.Fatalf("%T: size %d, has(1)=%v", c, c.Size(), c.Has(1))
+		}
+	}
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	c, _ := lru.New[int, int](64, nil)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.Push(g*1000+i, i)
+				c.Load(g*1000 + i/2)
+			}
+		}(g)
+	}
+	wg.Wait()
+	if c.Size() != 64 {
+		t.Fatalf("Size() = %d, want 64", c.Size())
+	}
+}
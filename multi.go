@@ -0,0 +1,67 @@
+package ringcache
+
+import "slices"
+
+// MultiRingCache is a bounded set of per-key buffers: Append adds an item to the slice stored
+// under a key, and the ring evicts whole keys, dropping their entire slice at once. It suits
+// buffering events per key until eviction without forcing callers to handle []T values. It is
+// a thin wrapper over RingCache with the same ring, eviction and concurrency semantics.
+type MultiRingCache[K comparable, T any] struct {
+	rc *RingCache[K, []T]
+}
+
+// NewMultiRingCache creates a MultiRingCache holding the buffers of up to capacity (> 0) keys.
+// onEvict, if not nil, is called outside the lock with every buffer evicted by the ring or
+// removed.
+func NewMultiRingCache[K comparable, T any](capacity int, onEvict func(key K, items []T)) (*MultiRingCache[K, T], error) {
+	rc, err := NewWithEvictCallback[K, []T](capacity, onEvict)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiRingCache[K, T]{rc: rc}, nil
+}
+
+// Append adds item to the end of key's buffer. A key already cached keeps its ring position,
+// so appending never evicts; a new key is pushed as the newest entry with a one-item buffer,
+// evicting the oldest key if the ring is full. Returns true if a key was evicted.
+func (m *MultiRingCache[K, T]) Append(key K, item T) (evicted bool) {
+	c := m.rc
+	c.mu.Lock()
+	if items, ok := c.items[key]; ok {
+		if !c.frozen {
+			c.setValue(key, append(items, item))
+		}
+		c.mu.Unlock()
+		return false
+	}
+	victims, _ := c.push(nil, key, []T{item})
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(victims...)
+	runHook(edge)
+	return len(victims) > 0
+}
+
+// LoadAll returns a copy of key's buffer in append order, or (nil, false) if key is absent.
+func (m *MultiRingCache[K, T]) LoadAll(key K) ([]T, bool) {
+	m.rc.mu.RLock()
+	defer m.rc.mu.RUnlock()
+	items, ok := m.rc.items[key]
+	return slices.Clone(items), ok
+}
+
+// Delete removes key and its whole buffer, returning true if it was present.
+func (m *MultiRingCache[K, T]) Delete(key K) bool {
+	return m.rc.Delete(key)
+}
+
+// Len returns the number of keys with a buffer.
+func (m *MultiRingCache[K, T]) Len() int {
+	return m.rc.Size()
+}
+
+// Capacity returns the maximum number of keys the cache holds buffers for.
+func (m *MultiRingCache[K, T]) Capacity() int {
+	return m.rc.Capacity()
+}
@@ -0,0 +1,55 @@
+package ringcache_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestMultiRingCache_AppendAndEvict(t *testing.T) {
+	var evictedKey string
+	var evictedItems []int
+	mc, err := ringcache.NewMultiRingCache[string, int](2, func(k string, items []int) {
+		evictedKey, evictedItems = k, items
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mc.Append("a", 1)
+	mc.Append("b", 10)
+	if mc.Append("a", 2) {
+		t.Fatalf("appending to an existing key must not evict")
+	}
+	if got, ok := mc.LoadAll("a"); !ok || !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("LoadAll(a) = %v, %v; want [1 2], true", got, ok)
+	}
+
+	// Appending kept "a" in place, so it is still the oldest key and is evicted whole.
+	if !mc.Append("c", 100) {
+		t.Fatalf("new key in a full cache should evict")
+	}
+	if evictedKey != "a" || !slices.Equal(evictedItems, []int{1, 2}) {
+		t.Fatalf("evicted %q %v, want a [1 2]", evictedKey, evictedItems)
+	}
+	if _, ok := mc.LoadAll("a"); ok {
+		t.Fatalf("a should be gone")
+	}
+	if mc.Len() != 2 || mc.Capacity() != 2 {
+		t.Fatalf("Len/Capacity = %d/%d, want 2/2", mc.Len(), mc.Capacity())
+	}
+}
+
+func TestMultiRingCache_LoadAllReturnsCopy(t *testing.T) {
+	mc, _ := ringcache.NewMultiRingCache[string, int](1, nil)
+	mc.Append("a", 1)
+	got, _ := mc.LoadAll("a")
+	got[0] = 99
+	if again, _ := mc.LoadAll("a"); again[0] != 1 {
+		t.Fatalf("LoadAll result aliases the buffer")
+	}
+	if !mc.Delete("a") || mc.Len() != 0 {
+		t.Fatalf("Delete should remove the buffer")
+	}
+}
@@ -0,0 +1,262 @@
+package ringcache
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Option configures optional RingCache behavior at construction time.
+// Options that register a callback or hook treat nil as "not set": passing nil disables the
+// hook (overriding an earlier non-nil value) and never causes a panic.
+type Option[K comparable, V any] func(*config[K, V])
+
+// config collects the settings applied by Options.
+type config[K comparable, V any] struct {
+	onEvict           EvictCallback[K, V]
+	sequence          bool
+	flushThreshold    int
+	flush             func([]Entry[K, V]) error
+	flushError        func([]Entry[K, V], error)
+	randSource        rand.Source
+	callbackTimeout   time.Duration
+	allowZeroCapacity bool
+	ageHistogram      bool
+	recoverCallbacks  bool
+	panicHandler      func(any)
+	indexes           map[string]func(K, V) string
+	batchEvict        func([]Entry[K, V])
+	latencyStats      bool
+	evictBatch        int
+	onFull            func()
+	onNotFull         func()
+	admit             func(K, V) bool
+	hitThreshold      float64
+	hitWindow         int
+	hitWarn           func(float64)
+	orderedClear      bool
+	onHit             func(K, V)
+	onMiss            func(K)
+	equal             func(a, b V) bool
+	score             func(K, V) int64
+	insertTimes       bool
+	asyncQueue        int
+	defaultTTL        time.Duration
+	clock             func() time.Time
+}
+
+// validate rejects inconsistent settings before a cache is built from them.
+func (cfg *config[K, V]) validate() error {
+	if cfg.flush != nil && cfg.flushThreshold <= 0 {
+		return errors.New("ringcache: write-back threshold must be greater than zero")
+	}
+	for name, extract := range cfg.indexes {
+		if extract == nil {
+			return fmt.Errorf("ringcache: index %q has no extract function", name)
+		}
+	}
+	if cfg.evictBatch < 0 {
+		return errors.New("ringcache: evict batch size must not be negative")
+	}
+	if cfg.hitWarn != nil && cfg.hitWindow <= 0 {
+		return errors.New("ringcache: hit ratio window must be greater than zero")
+	}
+	if cfg.defaultTTL < 0 {
+		return errors.New("ringcache: default TTL must not be negative")
+	}
+	if cfg.asyncQueue < 0 {
+		return errors.New("ringcache: async queue size must not be negative")
+	}
+	if cfg.callbackTimeout < 0 {
+		return errors.New("ringcache: callback timeout must not be negative")
+	}
+	return nil
+}
+
+// WithEvictCallback sets the eviction callback, like the cb argument of NewWithEvictCallback.
+// When both are given the option wins; WithEvictCallback(nil) disables the callback.
+func WithEvictCallback[K comparable, V any](cb EvictCallback[K, V]) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.onEvict = cb }
+}
+
+// WithSequence makes the cache stamp every Push with a monotonically increasing
+// sequence number, readable through Sequence. Unlike slot positions, sequence numbers
+// keep their global order across ring wrap-around. It costs one uint64 per entry.
+func WithSequence[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.sequence = true }
+}
+
+// WithInsertTimestamps makes the cache record the time (see WithClock) of every Push, readable
+// through OldestAge and EntryInfo.InsertedAt. Like sequence numbers, a re-push of an existing
+// key restamps it. It costs one time.Time per entry and a clock read per Push.
+func WithInsertTimestamps[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.insertTimes = true }
+}
+
+// WithRandSource sets the single source of randomness shared by every randomized code path
+// of the cache (currently Sample). Pass a seeded source (e.g. rand.NewPCG) for reproducible
+// results in tests. Without it the cache uses the automatically seeded global generator of
+// math/rand/v2. The cache serializes access to src, so it need not be safe for concurrent use.
+func WithRandSource[K comparable, V any](src rand.Source) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.randSource = src }
+}
+
+// WithSeed is shorthand for WithRandSource with a PCG source seeded from seed, making all
+// randomized behavior of the cache deterministic.
+func WithSeed[K comparable, V any](seed uint64) Option[K, V] {
+	return WithRandSource[K, V](rand.NewPCG(seed, seed))
+}
+
+// WithCallbackTimeout bounds how long an operation waits for each eviction callback
+// (per-entry or batch).
+// Each invocation runs on its own goroutine; if it has not returned after d, the operation
+// stops waiting, counts it in Stats().CallbackTimeouts and carries on. Abandoning a callback
+// is best effort: it keeps running in the background and whatever work it started is not
+// cancelled. A panic is raised again on the waiting caller's goroutine, as it would be inline;
+// one raised after the caller stopped waiting goes to the WithPanicHandler handler, if any, and
+// is otherwise discarded. Zero (the default) runs callbacks inline with no timeout.
+func WithCallbackTimeout[K comparable, V any](d time.Duration) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.callbackTimeout = d }
+}
+
+// WithAllowZeroCapacity lets New accept a capacity of 0 and build a pass-through cache that
+// stores nothing: every Push reports no eviction and every Load misses. It lets callers switch
+// caching off through configuration without touching call sites. Negative capacities are
+// still rejected.
+func WithAllowZeroCapacity[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.allowZeroCapacity = true }
+}
+
+// WithEvictionAgeHistogram records, for every entry evicted by the ring, how many Pushes
+// happened between its insertion and its eviction; see EvictionAgeHistogram. Ages are derived
+// from sequence numbers, so this option implies WithSequence.
+func WithEvictionAgeHistogram[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.sequence = true
+		cfg.ageHistogram = true
+	}
+}
+
+// WithRecoverCallbacks makes the cache recover panics raised by eviction callbacks, so a
+// buggy callback cannot crash the goroutine calling Push, Delete or Clear. Recovered panics
+// are passed to the WithPanicHandler handler, if any, and otherwise discarded.
+// By default panics propagate to the caller unchanged.
+func WithRecoverCallbacks[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.recoverCallbacks = true }
+}
+
+// WithPanicHandler receives the value of every panic recovered from an eviction callback.
+// It implies WithRecoverCallbacks; with a nil handler recovered panics are discarded.
+func WithPanicHandler[K comparable, V any](h func(recovered any)) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.recoverCallbacks = true
+		cfg.panicHandler = h
+	}
+}
+
+// WithBatchEvictCallback registers a callback invoked once per operation with every entry that
+// operation removed (one for a ring eviction or Delete, all of them for Clear), which suits sinks
+// that write in bulk. It is called outside the lock and may keep the slice.
+// If an EvictCallback is configured too, both run: first the per-entry callback for each entry,
+// then the batch callback.
+func WithBatchEvictCallback[K comparable, V any](cb func(entries []Entry[K, V])) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.batchEvict = cb }
+}
+
+// WithLatencyStats makes Push and PushEvicting measure how long they wait for the write lock
+// and report it in Stats (WriteLockWaits, WriteLockWaitNanos, MaxWriteLockWaitNanos). Long
+// waits point at contention, e.g. writers serialized behind a slow operation. It costs two
+// clock reads per Push, so it is off by default.
+func WithLatencyStats[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.latencyStats = true }
+}
+
+// WithEvictBatch changes the eviction cadence for batch-oriented backends: when a Push finds the
+// ring full, it evicts the n oldest evictable entries at once instead of just one, and the slots
+// it freed absorb the next n-1 new keys without further evictions. Callbacks therefore fire
+// about n times less often, each time with n entries (in one call, with WithBatchEvictCallback).
+// Pinned entries are skipped as usual. n of 0 or 1 keeps the default of one eviction per Push.
+func WithEvictBatch[K comparable, V any](n int) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.evictBatch = n }
+}
+
+// WithOnFull registers a hook called when the cache becomes full, i.e. Size() reaches
+// Capacity(). It fires on the transition edge only, at most once per transition, not on every
+// Push while the cache stays full. It runs outside the lock, after any eviction callbacks of
+// the same operation.
+func WithOnFull[K comparable, V any](f func()) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.onFull = f }
+}
+
+// WithOnNotFull registers a hook called when a full cache drops below full occupancy (Delete,
+// Clear, Swap, ...). Like WithOnFull it fires at most once per transition, outside the lock.
+func WithOnNotFull[K comparable, V any](f func()) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.onNotFull = f }
+}
+
+// WithAdmit registers an admission rule consulted by Push, PushEvicting, PushChanged and TryPush
+// before anything is evicted: if admit returns false the entry is not stored and nothing is evicted
+// (Push reports false, TryPush returns ErrRejected). It applies to updates of existing keys
+// too, but not to promotion (Touched, LoadAndTouch) or ReadBinary.
+// admit runs under the cache's write lock, so it must be fast and must not call the cache.
+func WithAdmit[K comparable, V any](admit func(key K, value V) bool) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.admit = admit }
+}
+
+// WithLowHitRatioWarning calls warn when the hit ratio of Load drops below threshold, a sign
+// that the capacity is too small. The ratio is measured over windows of window consecutive
+// Load calls (operations, not time; Has is not counted), evaluated each time a window
+// completes. warn receives the ratio of the offending window and fires once per breach: further
+// low windows stay silent until a window reaches the threshold again. It runs outside the
+// cache lock on the goroutine whose Load completed the window. A nil warn disables the check.
+func WithLowHitRatioWarning[K comparable, V any](threshold float64, window int, warn func(ratio float64)) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.hitThreshold = threshold
+		cfg.hitWindow = window
+		cfg.hitWarn = warn
+	}
+}
+
+// WithOrderedClear makes Clear hand its entries to the eviction callbacks and the write-back
+// buffer in ring order, oldest first, as the ring would have evicted them, e.g. for callbacks
+// that append to an ordered log. It costs a walk over all slots instead of over the entries, so
+// by default Clear stays unordered (map iteration order) for speed.
+func WithOrderedClear[K comparable, V any]() Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.orderedClear = true }
+}
+
+// WithOnHit registers a hook called, outside the lock, every time Load (or LoadOrDefault) finds
+// its key, with the key and the value returned; misses never call it. It runs synchronously on
+// the read hot path, so a slow hook slows every hit: keep it cheap, e.g. an atomic counter per
+// key, and hand heavier analytics off to another goroutine.
+func WithOnHit[K comparable, V any](f func(key K, value V)) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.onHit = f }
+}
+
+// WithOnMiss registers a hook called, outside the lock, every time Load (or LoadOrDefault) does
+// not find its key — the attachment point for multi-level caches, e.g. fetching from a slower
+// tier and pushing the result. It fires for every miss, so keep it cheap or throttle it
+// yourself. Has is a membership test and never calls it.
+func WithOnMiss[K comparable, V any](f func(key K)) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.onMiss = f }
+}
+
+// WithSkipNoopUpdates makes a Push of an existing key whose stored value equals (==) the new one
+// a complete no-op: the entry is not moved to the newest position, nothing is evicted, and
+// neither its version nor the cache's Generation changes, so watchers of either see no spurious
+// update. PushChanged reports whether a push was skipped. It requires a comparable V. SetValue
+// and CompareVersionAndSet are explicit updates and always write.
+func WithSkipNoopUpdates[K comparable, V comparable]() Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.equal = func(a, b V) bool { return a == b } }
+}
+
+// WithScoreEvict replaces the ring's oldest-first eviction with a priority rule: when a Push
+// needs room, it evicts the unpinned entry with the lowest score(key, value), the oldest one
+// among equal scores, and the new entry takes its slot. The API is unchanged and WouldEvict
+// follows the rule, but finding the minimum scans every entry, so each evicting Push costs
+// O(Capacity()) score calls under the write lock; keep score cheap and capacities moderate.
+// WithEvictBatch is ignored in this mode: one entry is evicted per Push.
+func WithScoreEvict[K comparable, V any](score func(key K, value V) int64) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.score = score }
+}
@@ -0,0 +1,33 @@
+package ringcache
+
+import "strings"
+
+// DeletePrefix removes every entry of rc whose key starts with prefix and returns how many it
+// removed. The scan and all removals happen under one write lock, which is much cheaper than
+// listing the keys and calling Delete for each; the eviction callbacks then run once per removed
+// entry, outside the lock, exactly as for Delete. A frozen cache is left untouched and 0 is
+// returned. An empty prefix matches every key.
+func DeletePrefix[V any](rc *RingCache[string, V], prefix string) int {
+	var removed []Entry[string, V]
+
+	rc.mu.Lock()
+	if rc.frozen {
+		rc.mu.Unlock()
+		return 0
+	}
+	for k := range rc.items {
+		if strings.HasPrefix(k, prefix) {
+			v, _ := rc.remove(k)
+			removed = append(removed, Entry[string, V]{Key: k, Value: v})
+		}
+	}
+	if len(removed) > 0 {
+		rc.gen.Add(1)
+	}
+	edge := rc.fullEdge()
+	rc.mu.Unlock()
+
+	rc.notifyEvicted(removed...)
+	runHook(edge)
+	return len(removed)
+}
@@ -0,0 +1,36 @@
+package ringcache_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestDeletePrefix(t *testing.T) {
+	var evicted []string
+	rc, _ := ringcache.NewWithEvictCallback[string, int](5, func(k string, _ int) {
+		evicted = append(evicted, k)
+	})
+	rc.Push("user:1:name", 1)
+	rc.Push("user:1:mail", 2)
+	rc.Push("user:12:name", 3)
+	rc.Push("order:1", 4)
+
+	if n := ringcache.DeletePrefix(rc, "user:1:"); n != 2 {
+		t.Fatalf("DeletePrefix removed %d entries, want 2", n)
+	}
+	sort.Strings(evicted)
+	if len(evicted) != 2 || evicted[0] != "user:1:mail" || evicted[1] != "user:1:name" {
+		t.Fatalf("callback saw %v", evicted)
+	}
+	if !rc.Has("user:12:name") || !rc.Has("order:1") || rc.Size() != 2 {
+		t.Fatalf("unrelated keys must survive, size = %d", rc.Size())
+	}
+	if n := ringcache.DeletePrefix(rc, "nope:"); n != 0 {
+		t.Fatalf("no key matches, removed %d", n)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("cache unhealthy after DeletePrefix: %v", err)
+	}
+}
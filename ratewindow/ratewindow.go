@@ -0,0 +1,100 @@
+// Package ratewindow counts events over a sliding time window on top of a RingCache.
+//
+// The window is split into fixed-width time buckets; each bucket is one cache entry keyed by
+// its bucket number. New buckets are pushed in time order, so the ring's FIFO eviction ages out
+// the oldest bucket exactly when a new one is needed and memory stays bounded by the number of
+// buckets. The package only uses the public ringcache API.
+package ratewindow
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chi07/ringcache"
+)
+
+// RateWindow counts events in a sliding window of fixed length. It is safe for concurrent use.
+type RateWindow struct {
+	mu     sync.Mutex
+	width  time.Duration
+	now    func() time.Time
+	counts *ringcache.RingCache[int64, int]
+}
+
+// Option configures optional RateWindow behavior.
+type Option func(*RateWindow)
+
+// WithClock replaces time.Now as the source of the current time, e.g. with a fake clock in
+// tests. The clock is expected not to go backwards.
+func WithClock(now func() time.Time) Option {
+	return func(w *RateWindow) {
+		if now != nil {
+			w.now = now
+		}
+	}
+}
+
+// New returns a RateWindow covering window, split into buckets equally wide buckets. More
+// buckets give finer CountLast resolution at the cost of one cache entry each.
+func New(window time.Duration, buckets int, opts ...Option) (*RateWindow, error) {
+	if buckets <= 0 {
+		return nil, errors.New("ratewindow: buckets must be greater than zero")
+	}
+	if window < time.Duration(buckets) {
+		return nil, errors.New("ratewindow: window too short for the number of buckets")
+	}
+	counts, err := ringcache.New[int64, int](buckets)
+	if err != nil {
+		return nil, err
+	}
+	w := &RateWindow{
+		width:  window / time.Duration(buckets),
+		now:    time.Now,
+		counts: counts,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// bucket returns the number of the bucket containing the current time.
+func (w *RateWindow) bucket() int64 {
+	return w.now().UnixNano() / int64(w.width)
+}
+
+// Incr records one event at the current time.
+func (w *RateWindow) Incr() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b := w.bucket()
+	if n, ok := w.counts.Load(b); ok {
+		// SetValue keeps the bucket in place; re-pushing it could evict the oldest bucket.
+		_ = w.counts.SetValue(b, n+1)
+		return
+	}
+	w.counts.Push(b, 1)
+}
+
+// CountLast returns the number of events recorded within the last d, rounded up to whole
+// buckets. d is capped at the window length; a non-positive d counts nothing.
+func (w *RateWindow) CountLast(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	n := int64((d + w.width - 1) / w.width)
+	if limit := int64(w.counts.Capacity()); n > limit {
+		n = limit
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	cur := w.bucket()
+	var total int
+	for i := int64(0); i < n; i++ {
+		c, _ := w.counts.Load(cur - i)
+		total += c
+	}
+	return total
+}
@@ -0,0 +1,84 @@
+package ratewindow_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chi07/ringcache/ratewindow"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestRateWindow_SlidingCount(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	w, err := ratewindow.New(10*time.Second, 10, ratewindow.WithClock(clk.now))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w.Incr()
+	w.Incr()
+	clk.advance(3 * time.Second)
+	w.Incr()
+
+	if got := w.CountLast(time.Second); got != 1 {
+		t.Fatalf("CountLast(1s) = %d, want 1", got)
+	}
+	if got := w.CountLast(10 * time.Second); got != 3 {
+		t.Fatalf("CountLast(10s) = %d, want 3", got)
+	}
+
+	// After the window has passed the first two events, only the third remains.
+	clk.advance(8 * time.Second)
+	if got := w.CountLast(time.Minute); got != 1 {
+		t.Fatalf("CountLast after 11s = %d, want 1", got)
+	}
+	clk.advance(10 * time.Second)
+	if got := w.CountLast(time.Minute); got != 0 {
+		t.Fatalf("CountLast after 21s = %d, want 0", got)
+	}
+}
+
+func TestRateWindow_OldBucketsEvicted(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(0, 0)}
+	w, _ := ratewindow.New(3*time.Second, 3, ratewindow.WithClock(clk.now))
+	for i := 0; i < 10; i++ {
+		w.Incr()
+		clk.advance(time.Second)
+	}
+	clk.advance(-time.Second)
+	if got := w.CountLast(3 * time.Second); got != 3 {
+		t.Fatalf("CountLast(3s) = %d, want 3", got)
+	}
+}
+
+func TestRateWindow_Concurrent(t *testing.T) {
+	w, _ := ratewindow.New(time.Hour, 4)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				w.Incr()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := w.CountLast(time.Hour); got != 800 {
+		t.Fatalf("CountLast = %d, want 800", got)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	if _, err := ratewindow.New(time.Second, 0); err == nil {
+		t.Fatalf("expected error for zero buckets")
+	}
+	if _, err := ratewindow.New(5, 10); err == nil {
+		t.Fatalf("expected error for a window shorter than one nanosecond per bucket")
+	}
+}
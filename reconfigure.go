@@ -0,0 +1,96 @@
+package ringcache
+
+import "fmt"
+
+// Reconfigure applies opts to a live cache in one step, e.g. on a configuration reload.
+// Options not mentioned keep their current setting. The new settings are validated as by New
+// and committed all at once under the write lock, or not at all if any option is rejected:
+// concurrent operations observe either the old or the new configuration, never a mix.
+// Operations already past the lock, such as callbacks in flight, finish with the settings they
+// started with.
+//
+// Runtime-mutable options are the callbacks and hooks (WithEvictCallback,
+// WithBatchEvictCallback, WithOnFull, WithOnNotFull, WithOnHit, WithOnMiss, WithAdmit,
+// WithLowHitRatioWarning), callback handling (WithCallbackTimeout, WithRecoverCallbacks,
+// WithPanicHandler) and eviction behavior (WithEvictBatch, WithScoreEvict, WithSkipNoopUpdates,
+// WithOrderedClear, WithLatencyStats, WithDefaultTTL). Pass a nil callback to remove one. A new
+// default TTL applies to later Pushes; existing expiries are kept. Options that shape the
+// cache's storage (WithSequence, WithInsertTimestamps, WithEvictionAgeHistogram, WithIndex,
+// WithWriteBack, WithWriteBackErrorHandler, WithRandSource, WithSeed, WithAllowZeroCapacity,
+// WithAsyncPush, WithClock) return an error; use Swap to change the capacity. Reconfiguring
+// restarts the current hit ratio window.
+func (c *RingCache[K, V]) Reconfigure(opts ...Option[K, V]) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h := c.hooks.Load()
+	cfg := config[K, V]{
+		onEvict:          h.onEvict,
+		batchEvict:       h.onEvictBatch,
+		callbackTimeout:  h.cbLimit,
+		recoverCallbacks: h.recoverCB,
+		panicHandler:     h.onPanic,
+		latencyStats:     h.latencyStats,
+		onHit:            h.onHit,
+		onMiss:           h.onMiss,
+		evictBatch:       c.evictBatch,
+		onFull:           c.onFull,
+		onNotFull:        c.onNotFull,
+		admit:            c.admit,
+		orderedClear:     c.orderedClear,
+		equal:            c.equal,
+		score:            c.score,
+		defaultTTL:       c.defaultTTL,
+	}
+	if w := h.hitWatch; w != nil {
+		cfg.hitThreshold, cfg.hitWindow, cfg.hitWarn = w.threshold, w.window, w.warn
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if name := cfg.fixedOption(); name != "" {
+		return fmt.Errorf("ringcache: %s cannot be changed after construction", name)
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	c.hooks.Store(newHooks(&cfg))
+	c.evictBatch = max(cfg.evictBatch, 1)
+	c.onFull = cfg.onFull
+	c.onNotFull = cfg.onNotFull
+	c.admit = cfg.admit
+	c.orderedClear = cfg.orderedClear
+	c.equal = cfg.equal
+	c.score = cfg.score
+	c.defaultTTL = cfg.defaultTTL
+	return nil
+}
+
+// fixedOption names a construction-only option that was applied to cfg, or returns "" if there
+// is none. Reconfigure starts from a config with all of them unset.
+func (cfg *config[K, V]) fixedOption() string {
+	switch {
+	case cfg.ageHistogram:
+		return "WithEvictionAgeHistogram"
+	case cfg.sequence:
+		return "WithSequence"
+	case cfg.insertTimes:
+		return "WithInsertTimestamps"
+	case cfg.allowZeroCapacity:
+		return "WithAllowZeroCapacity"
+	case cfg.indexes != nil:
+		return "WithIndex"
+	case cfg.flush != nil || cfg.flushThreshold != 0:
+		return "WithWriteBack"
+	case cfg.flushError != nil:
+		return "WithWriteBackErrorHandler"
+	case cfg.randSource != nil:
+		return "WithRandSource"
+	case cfg.asyncQueue != 0:
+		return "WithAsyncPush"
+	case cfg.clock != nil:
+		return "WithClock"
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package ringcache_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestReconfigure_ReplacesCallbacks(t *testing.T) {
+	var oldCalls, newCalls int
+	rc, _ := ringcache.NewWithEvictCallback[int, int](1, func(int, int) { oldCalls++ })
+	rc.Push(1, 1)
+
+	var misses []int
+	err := rc.Reconfigure(
+		ringcache.WithEvictCallback[int, int](func(int, int) { newCalls++ }),
+		ringcache.WithOnMiss[int, int](func(k int) { misses = append(misses, k) }),
+	)
+	if err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	rc.Push(2, 2)
+	rc.Load(9)
+	if oldCalls != 0 || newCalls != 1 || len(misses) != 1 {
+		t.Fatalf("old=%d new=%d misses=%v; want 0, 1, [9]", oldCalls, newCalls, misses)
+	}
+
+	// Settings not mentioned are kept; nil removes a callback.
+	if err := rc.Reconfigure(ringcache.WithEvictCallback[int, int](nil)); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	rc.Push(3, 3)
+	rc.Load(9)
+	if newCalls != 1 || len(misses) != 2 {
+		t.Fatalf("new=%d misses=%d; want 1, 2", newCalls, len(misses))
+	}
+}
+
+func TestReconfigure_EvictionSettings(t *testing.T) {
+	rc, _ := ringcache.New[int, int](4)
+	err := rc.Reconfigure(
+		ringcache.WithAdmit[int, int](func(_, v int) bool { return v >= 0 }),
+		ringcache.WithEvictBatch[int, int](2),
+	)
+	if err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	if rc.Push(1, -1) {
+		t.Fatal("admit rule not applied")
+	}
+	for i := 1; i <= 5; i++ {
+		rc.Push(i, i)
+	}
+	if rc.Size() != 3 {
+		t.Fatalf("size = %d, want 3 after a batch eviction of 2", rc.Size())
+	}
+}
+
+func TestReconfigure_AllOrNothing(t *testing.T) {
+	var calls int
+	rc, _ := ringcache.NewWithEvictCallback[int, int](1, func(int, int) { calls++ })
+	for _, opt := range []ringcache.Option[int, int]{
+		ringcache.WithSequence[int, int](),
+		ringcache.WithIndex[int, int]("x", func(int, int) string { return "" }),
+		ringcache.WithWriteBack[int, int](1, func([]ringcache.Entry[int, int]) error { return nil }),
+		ringcache.WithSeed[int, int](1),
+		ringcache.WithEvictBatch[int, int](-1), // invalid
+	} {
+		err := rc.Reconfigure(ringcache.WithEvictCallback[int, int](nil), opt)
+		if err == nil {
+			t.Fatal("Reconfigure accepted a construction-only or invalid option")
+		}
+	}
+	rc.Push(1, 1)
+	rc.Push(2, 2)
+	if calls != 1 {
+		t.Fatalf("rejected Reconfigure changed the callback: %d calls, want 1", calls)
+	}
+}
+
+func TestReconfigure_ConcurrentWithPush(t *testing.T) {
+	rc, _ := ringcache.New[int, int](8)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			rc.Push(i, i)
+			rc.Load(i - 1)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = rc.Reconfigure(
+				ringcache.WithEvictCallback[int, int](func(int, int) {}),
+				ringcache.WithOnHit[int, int](func(int, int) {}),
+				ringcache.WithLatencyStats[int, int](),
+			)
+		}
+	}()
+	wg.Wait()
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
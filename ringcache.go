@@ -2,10 +2,64 @@ package ringcache
 
 import (
 	"errors"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+var (
+	// ErrKeyNotFound is returned by operations that require the key to be present.
+	ErrKeyNotFound = errors.New("ringcache: key not found")
+
+	// ErrFrozen is returned by error-returning mutations while the cache is frozen.
+	ErrFrozen = errors.New("ringcache: cache is frozen")
+
+	// ErrAllPinned is returned by TryPush when every slot holds a pinned key.
+	ErrAllPinned = errors.New("ringcache: every slot is pinned")
+
+	// ErrRejected is returned by TryPush when the WithAdmit hook refuses an entry.
+	ErrRejected = errors.New("ringcache: entry rejected by admission hook")
+
+	// ErrSlotOutOfRange is returned by MoveToIndex for a slot outside [0, Capacity()).
+	ErrSlotOutOfRange = errors.New("ringcache: slot index out of range")
+
+	// ErrSlotOccupied is returned by MoveToIndex when the target slot holds another key.
+	ErrSlotOccupied = errors.New("ringcache: slot is occupied by another key")
+
+	// ErrClosed is returned by PushAsync after Close.
+	ErrClosed = errors.New("ringcache: cache is closed")
+)
+
+// Entry is a key/value pair held by the cache.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// EntryInfo describes a cached entry as reported by RingCache.Entry.
+// Fields tied to an option hold their zero value when that option is not enabled.
+type EntryInfo[V any] struct {
+	Value      V
+	Slot       int       // ring slot holding the entry
+	Sequence   uint64    // insertion sequence number; requires WithSequence
+	InsertedAt time.Time // time of the last Push; requires WithInsertTimestamps
+	ExpiresAt  time.Time // expiry set by PushWithTTL or WithDefaultTTL; zero if none
+	Pinned     bool
+}
+
 // EvictCallback is invoked when an entry is evicted (removed due to capacity or Delete()).
+//
+// Callbacks run synchronously on the goroutine of the operation that removed the entry, after
+// the cache lock is released and before that operation returns: once Push (or Delete, Clear,
+// ...) returns, every callback it triggered has completed and its side effects are visible to
+// the caller. The same holds for WithBatchEvictCallback and for a write-back flush triggered by
+// the operation. The exceptions are WithCallbackTimeout, which stops waiting for a callback
+// that exceeds the timeout, and PushAsync, whose callbacks run later on the writer goroutine.
 type EvictCallback[K comparable, V any] func(key K, value V)
 
 // RingCache is a fixed-size circular buffer (ring) cache that is thread-safe.
@@ -14,185 +68,1518 @@ type EvictCallback[K comparable, V any] func(key K, value V)
 //
 // Concurrency:
 //   - Writers (Push/Delete/Clear) use exclusive locking.
-//   - Readers (Load/Has/Size) use shared locking.
+//   - Readers (Load/Has) use shared locking; Size reads an atomic counter.
 //   - onEvict is ALWAYS invoked without holding the lock.
 type RingCache[K comparable, V any] struct {
-	capacity int       // immutable after construction
-	next     int       // next write index in the ring
-	keys     []K       // ring slots for keys
-	occupied []bool    // slot occupancy flags
-	items    map[K]V   // key -> value
-	pos      map[K]int // key -> ring slot index
-	onEvict  EvictCallback[K, V]
-	mu       sync.RWMutex
+	id           uint64                      // unique per cache; orders lock acquisition in Swap
+	capacity     int                         // changes only through Swap
+	next         int                         // next write index in the ring
+	keys         []K                         // ring slots for keys
+	occupied     []bool                      // slot occupancy flags
+	items        map[K]V                     // key -> value
+	pos          map[K]int                   // key -> ring slot index
+	pinned       map[K]struct{}              // keys Push must never evict
+	seq          uint64                      // last assigned sequence number
+	seqs         map[K]uint64                // key -> sequence number; nil unless WithSequence
+	versions     map[K]uint64                // key -> entry version, starting at 1 on insert
+	inserted     map[K]time.Time             // key -> time of last Push; nil unless WithInsertTimestamps
+	expires      map[K]time.Time             // key -> expiry of entries pushed with a TTL; nil until one is
+	minExpiry    time.Time                   // no entry in expires expires before this
+	defaultTTL   time.Duration               // TTL stamped by every Push; 0 unless WithDefaultTTL
+	clock        func() time.Time            // time.Now unless WithClock
+	ageHist      []uint64                    // eviction age buckets; nil unless WithEvictionAgeHistogram
+	indexes      map[string]*index[K, V]     // secondary indexes by name; nil unless WithIndex
+	size         atomic.Int64                // mirrors len(items) so Size needs no lock
+	gen          atomic.Uint64               // bumped on every change to the contents
+	hooks        atomic.Pointer[hooks[K, V]] // settings read without c.mu; replaced by Reconfigure
+	wb           *writeBack[K, V]            // nil unless WithWriteBack
+	async        *asyncWriter[K, V]          // nil unless WithAsyncPush
+	rng          *rand.Rand                  // nil means the global generator; guarded by rngMu
+	rngMu        sync.Mutex
+	frozen       bool              // set by Freeze; mutations become no-ops
+	evictBatch   int               // entries evicted per full Push; 1 unless WithEvictBatch
+	full         bool              // whether the cache was full at the last fullEdge check
+	onFull       func()            // nil unless WithOnFull
+	onNotFull    func()            // nil unless WithOnNotFull
+	admit        func(K, V) bool   // nil unless WithAdmit; called under c.mu
+	orderedClear bool              // Clear reports entries in ring order; set by WithOrderedClear
+	equal        func(a, b V) bool // value equality; nil unless WithSkipNoopUpdates
+	score        func(K, V) int64  // eviction priority; nil unless WithScoreEvict
+	entryPool    sync.Pool         // recycled *[]Entry buffers for Push and Clear
+	stats        counters
+	mu           sync.RWMutex
+}
+
+// hooks holds the callbacks and settings that operations read after releasing c.mu (or before
+// acquiring it). They are published as one immutable value, so an operation sees either all
+// of a Reconfigure or none of it.
+type hooks[K comparable, V any] struct {
+	onEvict      EvictCallback[K, V]
+	onEvictBatch func([]Entry[K, V]) // nil unless WithBatchEvictCallback
+	cbLimit      time.Duration       // callback timeout; 0 runs callbacks inline
+	recoverCB    bool                // recover panics raised by callbacks
+	onPanic      func(any)           // receives recovered panics; may be nil
+	latencyStats bool                // time write-lock waits in Push; set by WithLatencyStats
+	hitWatch     *hitWatch           // nil unless WithLowHitRatioWarning
+	onHit        func(K, V)          // nil unless WithOnHit
+	onMiss       func(K)             // nil unless WithOnMiss
+}
+
+// newHooks builds the hooks described by cfg.
+func newHooks[K comparable, V any](cfg *config[K, V]) *hooks[K, V] {
+	h := &hooks[K, V]{
+		onEvict:      cfg.onEvict,
+		onEvictBatch: cfg.batchEvict,
+		cbLimit:      cfg.callbackTimeout,
+		recoverCB:    cfg.recoverCallbacks,
+		onPanic:      cfg.panicHandler,
+		latencyStats: cfg.latencyStats,
+		onHit:        cfg.onHit,
+		onMiss:       cfg.onMiss,
+	}
+	if cfg.hitWarn != nil {
+		h.hitWatch = &hitWatch{threshold: cfg.hitThreshold, window: cfg.hitWindow, warn: cfg.hitWarn}
+	}
+	return h
 }
 
-// New creates a RingCache with the given capacity (> 0).
-func New[K comparable, V any](capacity int) (*RingCache[K, V], error) {
-	return NewWithEvictCallback[K, V](capacity, nil)
+// cacheIDs hands out RingCache ids.
+var cacheIDs atomic.Uint64
+
+// New creates a RingCache with the given capacity (> 0) and optional Options.
+// A capacity of 0 is only accepted together with WithAllowZeroCapacity.
+func New[K comparable, V any](capacity int, opts ...Option[K, V]) (*RingCache[K, V], error) {
+	return NewWithEvictCallback[K, V](capacity, nil, opts...)
 }
 
 // NewWithEvictCallback creates a RingCache with a given capacity and an optional eviction callback.
 // The callback will be called outside the internal lock.
-func NewWithEvictCallback[K comparable, V any](capacity int, cb EvictCallback[K, V]) (*RingCache[K, V], error) {
-	if capacity <= 0 {
+func NewWithEvictCallback[K comparable, V any](capacity int, cb EvictCallback[K, V], opts ...Option[K, V]) (*RingCache[K, V], error) {
+	cfg := config[K, V]{onEvict: cb}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if capacity < 0 || (capacity == 0 && !cfg.allowZeroCapacity) {
 		return nil, errors.New("ringcache: capacity must be greater than zero")
 	}
-	return &RingCache[K, V]{
-		capacity: capacity,
-		next:     0,
-		keys:     make([]K, capacity),
-		occupied: make([]bool, capacity),
-		items:    make(map[K]V, capacity),
-		pos:      make(map[K]int, capacity),
-		onEvict:  cb,
-	}, nil
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	c := &RingCache[K, V]{
+		id:           cacheIDs.Add(1),
+		capacity:     capacity,
+		next:         0,
+		keys:         make([]K, capacity),
+		occupied:     make([]bool, capacity),
+		items:        make(map[K]V, capacity),
+		pos:          make(map[K]int, capacity),
+		pinned:       make(map[K]struct{}),
+		versions:     make(map[K]uint64, capacity),
+		evictBatch:   max(cfg.evictBatch, 1),
+		onFull:       cfg.onFull,
+		onNotFull:    cfg.onNotFull,
+		admit:        cfg.admit,
+		orderedClear: cfg.orderedClear,
+		equal:        cfg.equal,
+		score:        cfg.score,
+		defaultTTL:   cfg.defaultTTL,
+		clock:        cfg.clock,
+	}
+	if c.clock == nil {
+		c.clock = time.Now
+	}
+	c.hooks.Store(newHooks(&cfg))
+	if cfg.sequence {
+		c.seqs = make(map[K]uint64, capacity)
+	}
+	if cfg.insertTimes {
+		c.inserted = make(map[K]time.Time, capacity)
+	}
+	if cfg.ageHistogram {
+		c.ageHist = make([]uint64, ageBuckets)
+	}
+	for name, extract := range cfg.indexes {
+		if c.indexes == nil {
+			c.indexes = make(map[string]*index[K, V], len(cfg.indexes))
+		}
+		c.indexes[name] = &index[K, V]{extract: extract, attrs: make(map[string]map[K]struct{})}
+	}
+	if cfg.flush != nil {
+		c.wb = &writeBack[K, V]{threshold: cfg.flushThreshold, flush: cfg.flush, onError: cfg.flushError}
+	}
+	if cfg.randSource != nil {
+		c.rng = rand.New(cfg.randSource)
+	}
+	if cfg.asyncQueue > 0 {
+		c.async = newAsyncWriter(c, cfg.asyncQueue)
+	}
+	return c, nil
 }
 
 // Clear removes all entries from the cache.
-// If an eviction callback is set, it's called for each removed entry (outside the lock).
+// If an eviction callback is set, it's called for each removed entry (outside the lock), in no
+// particular order unless the cache was created WithOrderedClear.
+// The cache is already empty when the callbacks run. With WithRecoverCallbacks each callback
+// is recovered on its own: a panicking callback is reported to the panic handler and Clear goes
+// on with the remaining entries. Without it the first panic propagates to the caller and the
+// callbacks for the remaining entries are skipped.
 func (c *RingCache[K, V]) Clear() {
-	var toEvict []struct {
-		k K
-		v V
-	}
+	var toEvict []Entry[K, V]
+	// A batch callback may keep its slice; otherwise the slice is only read during the
+	// callbacks below and can be recycled.
+	var buf *[]Entry[K, V]
 
 	c.mu.Lock()
+	if c.frozen {
+		c.mu.Unlock()
+		return
+	}
+	// Reconfigure holds c.mu, so h stays current until the unlock below.
+	h := c.hooks.Load()
 	// Collect items for eviction callback (if any)
-	if c.onEvict != nil && len(c.items) > 0 {
-		toEvict = make([]struct {
-			k K
-			v V
-		}, 0, len(c.items))
-		for k, v := range c.items {
-			toEvict = append(toEvict, struct {
-				k K
-				v V
-			}{k: k, v: v})
-		}
-	}
-
-	// Re-initialize internal state
-	c.items = make(map[K]V, c.capacity)
-	c.pos = make(map[K]int, c.capacity)
-	c.keys = make([]K, c.capacity)
-	for i := range c.occupied {
-		c.occupied[i] = false
+	if (h.onEvict != nil || h.onEvictBatch != nil || c.wb != nil) && len(c.items) > 0 {
+		if h.onEvictBatch == nil {
+			buf = c.getEntryBuf()
+			toEvict = *buf
+		} else {
+			toEvict = make([]Entry[K, V], 0, len(c.items))
+		}
+		if c.orderedClear {
+			toEvict = c.appendEntries(toEvict)
+		} else {
+			for k, v := range c.items {
+				toEvict = append(toEvict, Entry[K, V]{Key: k, Value: v})
+			}
+		}
 	}
-	c.next = 0
+
+	if len(c.items) > 0 {
+		c.gen.Add(1)
+	}
+	c.reset()
+	edge := c.fullEdge()
 	c.mu.Unlock()
 
 	// Invoke callbacks without holding the lock
-	if c.onEvict != nil {
-		for _, kv := range toEvict {
-			c.onEvict(kv.k, kv.v)
-		}
+	c.notifyWith(h, toEvict)
+	if buf != nil {
+		c.putEntryBuf(buf, toEvict)
+	}
+	runHook(edge)
+}
+
+// getEntryBuf returns a buffer holding an empty entry slice from the cache's pool, or a new one
+// if the pool is empty.
+func (c *RingCache[K, V]) getEntryBuf() *[]Entry[K, V] {
+	if buf, ok := c.entryPool.Get().(*[]Entry[K, V]); ok {
+		return buf
+	}
+	return new([]Entry[K, V])
+}
+
+// putEntryBuf stores entries, grown from *buf, back in buf and returns it to the pool, zeroed so
+// it keeps no keys or values reachable. Reusing buf keeps the Put itself allocation-free.
+func (c *RingCache[K, V]) putEntryBuf(buf *[]Entry[K, V], entries []Entry[K, V]) {
+	clear(entries)
+	*buf = entries[:0]
+	c.entryPool.Put(buf)
+}
+
+// RotateSnapshot atomically returns every entry and empties the cache in the same locked
+// section, so no Push can land between the read and the reset: each entry ends up either in the
+// returned map or in the cache afterwards, never both and never neither. This makes it the right
+// primitive for windowed aggregation (flush-and-reset), unlike separate snapshot and Clear
+// calls. The caller owns the returned data, so no eviction callback fires and write-back is not
+// involved. A frozen cache is left untouched and nil is returned.
+func (c *RingCache[K, V]) RotateSnapshot() map[K]V {
+	c.mu.Lock()
+	if c.frozen {
+		c.mu.Unlock()
+		return nil
+	}
+	snap := c.items
+	if len(snap) > 0 {
+		c.gen.Add(1)
+	}
+	c.items = make(map[K]V, c.capacity) // reset would clear the map handed to the caller
+	c.reset()
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	runHook(edge)
+	return snap
+}
+
+// reset re-initializes the internal state to an empty ring. It empties the existing maps and
+// slices in place rather than allocating new ones, so a Clear does not regrow them.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) reset() {
+	clear(c.items)
+	clear(c.pos)
+	clear(c.pinned)
+	clear(c.versions)
+	clear(c.seqs)
+	clear(c.inserted)
+	clear(c.expires)
+	for _, ix := range c.indexes {
+		ix.attrs = make(map[string]map[K]struct{})
 	}
+	clear(c.keys)
+	clear(c.occupied)
+	c.next = 0
+	c.size.Store(0)
 }
 
 // Push inserts (key, value) into the ring.
 // If the next slot is occupied by another key, that key is evicted.
+// Slots holding pinned keys are skipped: the write goes to the first slot after them
+// that is free or holds an unpinned key.
 // If the key already exists, its previous slot is freed (no eviction callback) and the key is re-inserted at the head.
+// If every slot holds a pinned key and the key is not already present, nothing is stored.
 // Returns true if an eviction occurred.
 func (c *RingCache[K, V]) Push(key K, value V) (evicted bool) {
+	c.lockTimed()
+	// Reconfigure holds c.mu, so h stays current until the unlock below. A batch callback may
+	// keep its slice; otherwise the victims are only read by the callbacks and are recycled.
+	h := c.hooks.Load()
 	var (
-		evictKey   *K
-		evictValue V
+		buf     *[]Entry[K, V]
+		victims []Entry[K, V]
 	)
+	if h.onEvictBatch == nil {
+		buf = c.getEntryBuf()
+		victims = *buf
+	}
+	victims, _, _ = c.store(victims, key, value)
+	edge := c.fullEdge()
+	c.mu.Unlock()
 
-	c.mu.Lock()
+	// Call eviction callback without holding the lock.
+	c.notifyWith(h, victims)
+	evicted = len(victims) > 0
+	if buf != nil {
+		c.putEntryBuf(buf, victims)
+	}
+	runHook(edge)
+	return evicted
+}
+
+// PushEvicting behaves like Push but also returns the evicted pair, if any, so callers can
+// capture a one-off eviction without setting up a callback. The eviction callback and the
+// write-back buffer still receive the evicted entry as they would for Push. Under
+// WithEvictBatch the oldest of the evicted entries is returned; the callbacks receive all.
+func (c *RingCache[K, V]) PushEvicting(key K, value V) (evictedKey K, evictedValue V, evicted bool) {
+	c.lockTimed()
+	victims, _, _ := c.store(nil, key, value)
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(victims...)
+	runHook(edge)
+	if len(victims) == 0 {
+		return evictedKey, evictedValue, false
+	}
+	return victims[0].Key, victims[0].Value, true
+}
+
+// PushChanged behaves like Push but also reports whether the cache actually changed. changed is
+// false when nothing was stored (frozen cache, rejected by WithAdmit, every slot pinned, zero
+// capacity) and, under WithSkipNoopUpdates, when key already held an equal value.
+func (c *RingCache[K, V]) PushChanged(key K, value V) (changed, evicted bool) {
+	c.lockTimed()
+	victims, changed, _ := c.store(nil, key, value)
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(victims...)
+	runHook(edge)
+	return changed, len(victims) > 0
+}
+
+// TryPush is Push with an error-first result for callers that must surface every failure. It
+// reports whether an entry was evicted and returns ErrFrozen if the cache is frozen,
+// ErrRejected if the WithAdmit hook refused the entry, or ErrAllPinned if every slot holds a
+// pinned key; in all three cases nothing is stored or evicted. A cache of zero capacity
+// (WithAllowZeroCapacity) stores nothing by design and returns no error, and neither does a
+// push skipped by WithSkipNoopUpdates.
+func (c *RingCache[K, V]) TryPush(key K, value V) (evicted bool, err error) {
+	c.lockTimed()
+	victims, _, err := c.store(nil, key, value)
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(victims...)
+	runHook(edge)
+	return len(victims) > 0, err
+}
+
+// PushAllOrdered pushes entries in slice order under a single lock, as consecutive Pushes would,
+// and reports the outcome. Order decides survival: later entries win, so when entries exceed
+// the free room only the last ones remain (the last Capacity() of them if nothing is pinned).
+// survivors lists the input keys cached at the end with their pushed value, oldest first, each
+// once; evicted lists every entry the ring evicted on the way, in eviction order, including
+// entries from entries itself and pre-existing ones. A key evicted and pushed again appears in
+// both. Entries refused by the frozen state or WithAdmit are in neither. Callbacks fire outside
+// the lock after all entries are pushed; a batch callback receives all evicted entries at once,
+// in a slice of its own.
+func (c *RingCache[K, V]) PushAllOrdered(entries []Entry[K, V]) (survivors []K, evicted []Entry[K, V]) {
+	last := make(map[K]int, len(entries)) // input index of the last accepted push of each key
+	c.lockTimed()
+	for i, e := range entries {
+		var err error
+		evicted, _, err = c.store(evicted, e.Key, e.Value)
+		if err == nil {
+			last[e.Key] = i
+		}
+	}
+	for i, e := range entries {
+		if j, ok := last[e.Key]; ok && j == i {
+			if _, ok := c.items[e.Key]; ok {
+				survivors = append(survivors, e.Key)
+			}
+		}
+	}
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	// A batch callback may keep its slice, so it gets a copy rather than the returned one.
+	h := c.hooks.Load()
+	if h.onEvictBatch != nil {
+		c.notifyWith(h, slices.Clone(evicted))
+	} else {
+		c.notifyWith(h, evicted)
+	}
+	runHook(edge)
+	return survivors, evicted
+}
+
+// store implements the Push family on top of push: it applies the frozen state, the WithAdmit
+// hook and WithSkipNoopUpdates in that order, then pushes. It returns the evicted entries,
+// whether the entry was written, and why not (nil for an unchanged value or zero capacity).
+// The evicted entries are appended to dst, which may be nil. Caller must hold c.mu.
+func (c *RingCache[K, V]) store(dst []Entry[K, V], key K, value V) (victims []Entry[K, V], stored bool, err error) {
+	if c.frozen {
+		return dst, false, ErrFrozen
+	}
+	if c.admit != nil && !c.admit(key, value) {
+		return dst, false, ErrRejected
+	}
+	if c.equal != nil {
+		if old, ok := c.items[key]; ok && c.equal(old, value) {
+			return dst, false, nil
+		}
+	}
+	victims, stored = c.push(dst, key, value)
+	if !stored && c.capacity > 0 {
+		err = ErrAllPinned
+	}
+	return victims, stored, err
+}
+
+// push implements Push without locking or callbacks. It returns the evicted entries, oldest
+// first (at most one unless WithEvictBatch is set) appended to dst, and whether the entry was
+// stored. Caller must hold c.mu.
+func (c *RingCache[K, V]) push(dst []Entry[K, V], key K, value V) (victims []Entry[K, V], stored bool) {
+	victims = dst
+	if c.frozen || c.capacity == 0 {
+		return victims, false
+	}
 
 	// If key already exists, free its old slot (we "move" it).
-	if oldPos, exists := c.pos[key]; exists {
+	oldPos, exists := c.pos[key]
+	if exists {
 		c.occupied[oldPos] = false
 		// Keep items[key] alive; we overwrite it below with the new value.
+		c.indexRemove(key, c.items[key])
 	}
 
-	// If the next slot is occupied, evict the existing key at that slot.
-	if c.occupied[c.next] {
-		oldKey := c.keys[c.next]
-		if v, ok := c.items[oldKey]; ok {
-			evictKey = &oldKey
-			evictValue = v
-			delete(c.items, oldKey)
-			delete(c.pos, oldKey)
-			evicted = true
+	slot, ok := c.nextSlot()
+	if !ok {
+		// Every slot is pinned; there is nowhere to put a new key.
+		return victims, false
+	}
+
+	// If the chosen slot is occupied, evict the existing key at that slot, and under
+	// WithEvictBatch the next oldest evictable entries too, leaving their slots free.
+	// An expired entry is reclaimed first, so no live entry is evicted while one exists.
+	// WithScoreEvict replaces the ring's choice with the lowest-scored entry.
+	if c.occupied[slot] {
+		if s, ok := c.expiredSlot(); ok {
+			k := c.keys[s]
+			v, _ := c.remove(k)
+			victims = append(victims, Entry[K, V]{Key: k, Value: v})
+			if s != slot {
+				// Close the gap so the ring keeps its order and the new key lands just
+				// before next, as the newest entry.
+				slot = c.shiftBack(s)
+				c.writeSlot(slot, key, value, exists)
+				return victims, true
+			}
+		} else if c.score != nil {
+			slot = c.minScoreSlot()
+			victims = append(victims, c.evictSlot(slot))
+		} else {
+			victims = append(victims, c.evictSlot(slot))
+			for i := 1; i < c.capacity && len(victims) < c.evictBatch; i++ {
+				s := (slot + i) % c.capacity
+				if !c.occupied[s] {
+					continue
+				}
+				if _, pinned := c.pinned[c.keys[s]]; !pinned {
+					victims = append(victims, c.evictSlot(s))
+				}
+			}
 		}
 	}
 
-	// Write the new key/value into the next slot.
-	c.keys[c.next] = key
-	c.occupied[c.next] = true
+	c.writeSlot(slot, key, value, exists)
+	c.next = (slot + 1) % c.capacity
+	return victims, true
+}
+
+// writeSlot stores the pushed key/value into the free slot and stamps its per-key state.
+// exists reports whether key was already cached. Caller must hold c.mu.
+func (c *RingCache[K, V]) writeSlot(slot int, key K, value V, exists bool) {
+	c.keys[slot] = key
+	c.occupied[slot] = true
 	c.items[key] = value
-	c.pos[key] = c.next
-	c.next = (c.next + 1) % c.capacity
+	c.pos[key] = slot
+	c.versions[key]++
+	c.indexAdd(key, value)
+	// A Push takes the default TTL, if any; PushWithTTL overrides the expiry afterwards.
+	delete(c.expires, key)
+	if c.defaultTTL > 0 {
+		c.setExpiry(key, c.defaultTTL)
+	}
+	if c.seqs != nil {
+		c.seq++
+		c.seqs[key] = c.seq
+	}
+	if c.inserted != nil {
+		c.inserted[key] = c.now()
+	}
+	if !exists {
+		c.size.Add(1)
+	}
+	c.gen.Add(1)
+}
 
-	c.mu.Unlock()
+// shiftBack closes the free slot s by moving every slot after it, up to the newest one
+// (just before next), back by one, and returns the slot freed before next. next itself does
+// not move, so the ring order of the remaining entries is unchanged. Caller must hold c.mu.
+func (c *RingCache[K, V]) shiftBack(s int) int {
+	last := (c.next - 1 + c.capacity) % c.capacity
+	for s != last {
+		from := (s + 1) % c.capacity
+		c.keys[s], c.occupied[s] = c.keys[from], c.occupied[from]
+		if c.occupied[s] {
+			c.pos[c.keys[s]] = s
+		}
+		s = from
+	}
+	var zeroK K
+	c.keys[last], c.occupied[last] = zeroK, false
+	return last
+}
 
-	// Call eviction callback without holding the lock.
-	if evicted && c.onEvict != nil && evictKey != nil {
-		c.onEvict(*evictKey, evictValue)
+// minScoreSlot returns the slot of the unpinned entry with the lowest WithScoreEvict score,
+// breaking ties in ring order (oldest first). It scans the whole ring and must only be called
+// when at least one slot holds an unpinned entry. Caller must hold c.mu.
+func (c *RingCache[K, V]) minScoreSlot() int {
+	best, bestScore := -1, int64(0)
+	for i := 0; i < c.capacity; i++ {
+		s := (c.next + i) % c.capacity
+		if !c.occupied[s] {
+			continue
+		}
+		k := c.keys[s]
+		if _, pinned := c.pinned[k]; pinned {
+			continue
+		}
+		if sc := c.score(k, c.items[k]); best < 0 || sc < bestScore {
+			best, bestScore = s, sc
+		}
+	}
+	return best
+}
+
+// evictSlot removes the entry in the occupied slot s as a ring eviction, recording its age.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) evictSlot(s int) Entry[K, V] {
+	k := c.keys[s]
+	if c.ageHist != nil {
+		// The current Push takes sequence number c.seq+1.
+		c.ageHist[ageBucket(c.seq+1-c.seqs[k])]++
 	}
-	return
+	v, _ := c.remove(k)
+	return Entry[K, V]{Key: k, Value: v}
 }
 
 // Load returns (value, true) if the key exists; otherwise (zero, false).
+// Load never moves the entry in the ring, but it counts in Stats and runs the hit/miss hooks;
+// use Peek to observe the cache without any side effect. An entry whose PushWithTTL expiry has
+// passed counts as a miss and is removed on the spot, invoking the eviction callback.
+// The value is returned by copy and Load does not allocate; for very large V consider storing
+// a pointer type as V instead. The cache then shares each pointee with every caller of Load and
+// with the eviction callbacks, so it must not be mutated without synchronization.
 func (c *RingCache[K, V]) Load(key K) (V, bool) {
 	c.mu.RLock()
 	v, ok := c.items[key]
+	expired := ok && c.isExpired(key)
 	c.mu.RUnlock()
+	if expired {
+		c.expire(key)
+		var zero V
+		v, ok = zero, false
+	}
+	if ok {
+		c.stats.hits.Add(1)
+	} else {
+		c.stats.misses.Add(1)
+	}
+	h := c.hooks.Load()
+	if h.hitWatch != nil {
+		h.hitWatch.observe(ok)
+	}
+	if ok && h.onHit != nil {
+		h.onHit(key, v)
+	}
+	if !ok && h.onMiss != nil {
+		h.onMiss(key)
+	}
+	return v, ok
+}
+
+// LoadOrDefault returns the value stored for key, or def if key is absent. It never stores
+// def; use Push for that. It counts in the hit/miss statistics like Load.
+func (c *RingCache[K, V]) LoadOrDefault(key K, def V) V {
+	if v, ok := c.Load(key); ok {
+		return v
+	}
+	return def
+}
+
+// Peek returns the value stored for key like Load, but is guaranteed to have no side effect,
+// whatever eviction policy the cache uses: it never moves or refreshes the entry, is not
+// counted in Stats and runs no hooks. It reports an expired entry as absent without removing
+// it. It takes the read lock only, so monitoring code can call it freely.
+func (c *RingCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.items[key]
+	if ok && c.isExpired(key) {
+		var zero V
+		return zero, false
+	}
 	return v, ok
 }
 
-// Has reports whether the key exists in the cache.
+// PeekPos returns the ring slot holding key, with the same guarantees as Peek. Compared with
+// NextIndex it tells how close the entry is to eviction: under the default policy the ring
+// overwrites slots starting at NextIndex, skipping pinned ones. Returns (-1, false) if key is
+// absent or has expired.
+func (c *RingCache[K, V]) PeekPos(key K) (int, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.pos[key]
+	if !ok || c.isExpired(key) {
+		return -1, false
+	}
+	return p, true
+}
+
+// Entry returns everything the cache knows about key, read consistently under a single
+// read lock. Returns false if the key is absent or has expired.
+func (c *RingCache[K, V]) Entry(key K) (EntryInfo[V], bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.items[key]
+	if !ok || c.isExpired(key) {
+		return EntryInfo[V]{}, false
+	}
+	_, pinned := c.pinned[key]
+	return EntryInfo[V]{
+		Value:      v,
+		Slot:       c.pos[key],
+		Sequence:   c.seqs[key],
+		InsertedAt: c.inserted[key],
+		ExpiresAt:  c.expires[key],
+		Pinned:     pinned,
+	}, true
+}
+
+// SetValue replaces the value of an existing key in place and returns ErrKeyNotFound
+// if the key is absent, or ErrFrozen while the cache is frozen. Unlike Push it neither
+// moves the key in the ring nor evicts anything.
+func (c *RingCache[K, V]) SetValue(key K, value V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	if _, ok := c.items[key]; !ok {
+		return ErrKeyNotFound
+	}
+	c.setValue(key, value)
+	return nil
+}
+
+// setValue replaces the value of the cached key in place and bumps its version.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) setValue(key K, value V) {
+	c.indexRemove(key, c.items[key])
+	c.items[key] = value
+	c.indexAdd(key, value)
+	c.versions[key]++
+	c.gen.Add(1)
+}
+
+// Touched reports whether key exists and, if it does, promotes it to the newest position in
+// the ring, all in one write-lock acquisition (no Has-then-Push race). Promotion works like
+// re-pushing the current value: the key gets a new slot and sequence number, and the entry
+// occupying that slot, if any, is evicted as it would be by Push.
+func (c *RingCache[K, V]) Touched(key K) bool {
+	_, ok := c.LoadAndTouch(key)
+	return ok
+}
+
+// LoadAndTouch is Load plus promotion: it returns the value for key and, if the key exists,
+// moves it to the newest position exactly as Touched does, under a single write lock. Use it
+// when every read should count as a use; plain Load never changes the ring order. Promotion
+// keeps the entry's expiry, and an expired entry is a miss that is removed, as with Load.
+func (c *RingCache[K, V]) LoadAndTouch(key K) (V, bool) {
+	var victims []Entry[K, V]
+	c.mu.Lock()
+	v, ok := c.items[key]
+	if ok && c.isExpired(key) {
+		if !c.frozen {
+			c.remove(key)
+			c.gen.Add(1)
+			victims = append(victims, Entry[K, V]{Key: key, Value: v})
+		}
+		var zero V
+		v, ok = zero, false
+	}
+	if ok {
+		// Promotion is not an update: the entry keeps its version and expiry.
+		ver := c.versions[key]
+		exp, hasExp := c.expires[key]
+		victims, _ = c.push(nil, key, v)
+		c.versions[key] = ver
+		if hasExp {
+			c.setExpiryAt(key, exp)
+		} else {
+			delete(c.expires, key)
+		}
+	}
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(victims...)
+	runHook(edge)
+	return v, ok
+}
+
+// Has reports whether the key exists in the cache. It is counted in Stats().HasHits and
+// HasMisses, not in the Load hit/miss counters. Like Load it removes an expired entry and
+// reports it as absent.
 func (c *RingCache[K, V]) Has(key K) bool {
 	c.mu.RLock()
 	_, ok := c.items[key]
+	expired := ok && c.isExpired(key)
 	c.mu.RUnlock()
+	if expired {
+		c.expire(key)
+		ok = false
+	}
+	if ok {
+		c.stats.hasHits.Add(1)
+	} else {
+		c.stats.hasMisses.Add(1)
+	}
 	return ok
 }
 
 // Delete removes the key from the cache (if present) and returns true if it existed.
 // The eviction callback is invoked (outside the lock) if a key was actually removed.
 func (c *RingCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	if c.frozen {
+		c.mu.Unlock()
+		return false
+	}
+	val, had := c.remove(key)
+	if had {
+		c.gen.Add(1)
+	}
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	if had {
+		c.notifyEvicted(Entry[K, V]{Key: key, Value: val})
+	}
+	runHook(edge)
+	return had
+}
+
+// EvictSlot removes whatever occupies ring slot index and returns it, invoking the eviction
+// callback (outside the lock) as Delete does. It reports false when index is outside
+// [0, Capacity()), when the slot is empty, or when the cache is frozen.
+//
+// This is a low-level tool for code that rebuilds an exact ring layout (e.g. replication
+// replay). It bypasses the normal eviction order, removes pinned keys too, and slot numbers
+// are only meaningful while no other goroutine writes to the cache.
+func (c *RingCache[K, V]) EvictSlot(index int) (K, V, bool) {
 	var (
-		had  bool
-		val  V
-		call bool
+		key K
+		val V
 	)
+	c.mu.Lock()
+	if c.frozen || index < 0 || index >= c.capacity || !c.occupied[index] {
+		c.mu.Unlock()
+		return key, val, false
+	}
+	key = c.keys[index]
+	val, _ = c.remove(key)
+	c.gen.Add(1)
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(Entry[K, V]{Key: key, Value: val})
+	runHook(edge)
+	return key, val, true
+}
 
+// PopNewest removes and returns the most recently pushed entry still cached — the last one in
+// ring order — and rewinds the next write position to its slot, so it undoes the slot usage of
+// the last insert. Expired entries newer than it are removed on the way, as by Load. The
+// eviction callback runs outside the lock as for Delete, for every removed entry. It reports
+// false if the cache is frozen or holds no live entry.
+func (c *RingCache[K, V]) PopNewest() (K, V, bool) {
+	var (
+		key     K
+		val     V
+		found   bool
+		removed []Entry[K, V]
+	)
 	c.mu.Lock()
-	if p, ok := c.pos[key]; ok {
-		val = c.items[key]
-		delete(c.items, key)
-		delete(c.pos, key)
-		c.occupied[p] = false
+	if c.frozen || len(c.items) == 0 {
+		c.mu.Unlock()
+		return key, val, false
+	}
+	for i := 1; i <= c.capacity && !found; i++ {
+		s := (c.next - i + c.capacity) % c.capacity
+		if !c.occupied[s] {
+			continue
+		}
+		k := c.keys[s]
+		expired := c.isExpired(k)
+		v, _ := c.remove(k)
+		removed = append(removed, Entry[K, V]{Key: k, Value: v})
+		if !expired {
+			key, val, found = k, v, true
+			c.next = s
+		}
+	}
+	c.gen.Add(1)
+	edge := c.fullEdge()
+	c.mu.Unlock()
 
-		// Clear key slot to zero value (not required functionally, but useful for debugging/clarity).
-		var zeroK K
-		c.keys[p] = zeroK
+	c.notifyEvicted(removed...)
+	runHook(edge)
+	return key, val, found
+}
 
-		had = true
-		call = c.onEvict != nil
+// MoveToIndex relocates the cached key to the free ring slot index, keeping its value and all
+// per-entry state; the next write position is not changed. Moving a key to the slot it already
+// occupies is a no-op. It returns ErrKeyNotFound if key is absent, ErrSlotOutOfRange if index is
+// outside [0, Capacity()), ErrSlotOccupied if another key holds the slot (free it first with
+// EvictSlot), and ErrFrozen while the cache is frozen.
+//
+// Like EvictSlot this is a low-level tool for rebuilding exact ring layouts (tests, replication
+// replay): it changes eviction order and is only meaningful while no other goroutine writes.
+func (c *RingCache[K, V]) MoveToIndex(key K, index int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	from, ok := c.pos[key]
+	switch {
+	case c.frozen:
+		return ErrFrozen
+	case !ok:
+		return ErrKeyNotFound
+	case index < 0 || index >= c.capacity:
+		return ErrSlotOutOfRange
+	case from == index:
+		return nil
+	case c.occupied[index]:
+		return ErrSlotOccupied
 	}
+	var zeroK K
+	c.keys[from], c.occupied[from] = zeroK, false
+	c.keys[index], c.occupied[index] = key, true
+	c.pos[key] = index
+	return nil
+}
+
+// remove drops key and all of its bookkeeping and frees its slot, returning the removed value.
+// It does not bump the generation or run callbacks. Caller must hold c.mu.
+func (c *RingCache[K, V]) remove(key K) (V, bool) {
+	p, ok := c.pos[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	val := c.items[key]
+	c.indexRemove(key, val)
+	delete(c.items, key)
+	delete(c.pos, key)
+	delete(c.pinned, key)
+	delete(c.seqs, key)
+	delete(c.inserted, key)
+	delete(c.expires, key)
+	delete(c.versions, key)
+	c.occupied[p] = false
+	c.size.Add(-1)
+
+	// Clear key slot to zero value (not required functionally, but useful for debugging/clarity).
+	var zeroK K
+	c.keys[p] = zeroK
+	return val, true
+}
+
+// notifyEvicted hands the entries removed by one operation to the eviction callbacks and the
+// write-back buffer.
+// It must be called without holding c.mu.
+func (c *RingCache[K, V]) notifyEvicted(entries ...Entry[K, V]) {
+	c.notifyWith(c.hooks.Load(), entries)
+}
+
+// notifyWith is notifyEvicted with the hooks loaded by the caller.
+// It must be called without holding c.mu.
+func (c *RingCache[K, V]) notifyWith(h *hooks[K, V], entries []Entry[K, V]) {
+	if len(entries) == 0 {
+		return
+	}
+	if h.onEvict != nil {
+		for _, e := range entries {
+			c.callEvict(h, e.Key, e.Value)
+		}
+	}
+	if h.onEvictBatch != nil {
+		c.invoke(h, func() { h.onEvictBatch(entries) })
+	}
+	if c.wb != nil {
+		c.wb.add(entries)
+	}
+}
+
+// fullEdge records whether the cache is full now and, if that changed since the last call,
+// returns the matching WithOnFull / WithOnNotFull hook for the caller to run after unlocking.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) fullEdge() func() {
+	full := c.capacity > 0 && len(c.items) == c.capacity
+	if full == c.full {
+		return nil
+	}
+	c.full = full
+	if full {
+		return c.onFull
+	}
+	return c.onNotFull
+}
+
+// runHook calls f unless it is nil. It must be called without holding c.mu.
+func runHook(f func()) {
+	if f != nil {
+		f()
+	}
+}
+
+// callEvict invokes the eviction callback for one entry, honoring the callback timeout and
+// panic recovery options.
+func (c *RingCache[K, V]) callEvict(h *hooks[K, V], key K, value V) {
+	if h.cbLimit == 0 && !h.recoverCB {
+		h.onEvict(key, value)
+		return
+	}
+	c.invoke(h, func() { h.onEvict(key, value) })
+}
+
+// invoke runs a user callback, honoring the callback timeout and panic recovery options.
+func (c *RingCache[K, V]) invoke(h *hooks[K, V], f func()) {
+	call := f
+	if h.recoverCB {
+		call = func() {
+			defer h.recoverPanic()
+			f()
+		}
+	}
+	if h.cbLimit > 0 {
+		c.invokeWithTimeout(h, call)
+	} else {
+		call()
+	}
+}
+
+// recoverPanic recovers a panic and reports it to the panic handler. It must be deferred.
+func (h *hooks[K, V]) recoverPanic() {
+	if r := recover(); r != nil && h.onPanic != nil {
+		h.onPanic(r)
+	}
+}
+
+// Callback states shared by invokeWithTimeout and its goroutine.
+const (
+	callRunning int32 = iota
+	callReturned
+	callAbandoned
+)
+
+// callResult is the outcome of a callback run by invokeWithTimeout.
+type callResult struct {
+	recovered any
+	panicked  bool
+}
+
+// invokeWithTimeout runs f on a new goroutine and waits at most h.cbLimit for it to return.
+// A panic in f is recovered on that goroutine and raised again on the caller's, so it
+// propagates as it would inline. If the caller has stopped waiting, there is no one left to
+// raise it to: it goes to the panic handler, if any, and is otherwise discarded.
+func (c *RingCache[K, V]) invokeWithTimeout(h *hooks[K, V], f func()) {
+	var state atomic.Int32
+	done := make(chan callResult, 1)
+	go func() {
+		res := callResult{panicked: true}
+		defer func() {
+			if res.panicked {
+				res.recovered = recover()
+			}
+			if state.CompareAndSwap(callRunning, callReturned) {
+				done <- res
+			} else if res.panicked && h.onPanic != nil {
+				h.onPanic(res.recovered)
+			}
+		}()
+		f()
+		res.panicked = false
+	}()
+	t := time.NewTimer(h.cbLimit)
+	defer t.Stop()
+	var res callResult
+	select {
+	case res = <-done:
+	case <-t.C:
+		if state.CompareAndSwap(callRunning, callAbandoned) {
+			c.stats.callbackTimeouts.Add(1)
+			return
+		}
+		// f returned just as the timer fired.
+		res = <-done
+	}
+	if res.panicked {
+		panic(res.recovered)
+	}
+}
+
+// Freeze makes the cache read-only, e.g. to export a consistent copy during maintenance.
+// While frozen, content mutations are rejected without side effects: Push and PushEvicting
+// store nothing and report no eviction, Delete removes nothing and returns false, Clear does
+// nothing, and error-returning mutations (SetValue, ReadBinary, MoveToIndex) fail with ErrFrozen.
+// Reads, Pin and Unpin keep working. Freezing an already frozen cache is a no-op.
+func (c *RingCache[K, V]) Freeze() {
+	c.mu.Lock()
+	c.frozen = true
 	c.mu.Unlock()
+}
+
+// Unfreeze re-enables mutations after Freeze.
+func (c *RingCache[K, V]) Unfreeze() {
+	c.mu.Lock()
+	c.frozen = false
+	c.mu.Unlock()
+}
 
-	if had && call {
-		c.onEvict(key, val)
+// Frozen reports whether the cache is currently frozen.
+func (c *RingCache[K, V]) Frozen() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.frozen
+}
+
+// Generation returns a counter that increases every time the cache contents change
+// (a stored Push, a successful Delete, a non-empty Clear, ReadBinary). Pinning does not
+// count as a change. Reading it before and after a snapshot tells whether the cache was
+// modified in between; the exact amount it grows by is not part of the contract.
+// It is read atomically and never blocks. Being a uint64 it would only wrap to 0 after 2^64
+// changes (centuries at a billion changes per second); callers should compare generations
+// for equality rather than order.
+func (c *RingCache[K, V]) Generation() uint64 {
+	return c.gen.Load()
+}
+
+// Sequence returns the sequence number assigned to key by its most recent Push.
+// Sequence numbers start at 1 and increase with every Push, including re-pushes of
+// an existing key, and are never reused (not even after Clear). They are uint64 and do not
+// wrap in practice; the eviction age histogram derives ages by modular subtraction, so it
+// stays correct even across a wrap-around.
+// Returns (0, false) if the key is absent or the cache was not created WithSequence.
+func (c *RingCache[K, V]) Sequence(key K) (uint64, bool) {
+	c.mu.RLock()
+	s, ok := c.seqs[key]
+	c.mu.RUnlock()
+	return s, ok
+}
+
+// OldestAge returns how long ago the oldest live entry was pushed, i.e. the largest insertion
+// age in the cache, for staleness monitoring: it grows when churn drops. It measures time since
+// the entry's last Push, not time left until any expiry; expired entries not removed yet are
+// left out. ok is false if the cache holds no live entry or was not created
+// WithInsertTimestamps. It scans all entries under the read lock.
+func (c *RingCache[K, V]) OldestAge() (age time.Duration, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var oldest time.Time
+	for k, t := range c.inserted {
+		if c.isExpired(k) {
+			continue
+		}
+		if !ok || t.Before(oldest) {
+			oldest, ok = t, true
+		}
 	}
-	return had
+	if !ok {
+		return 0, false
+	}
+	return c.now().Sub(oldest), true
+}
+
+// ageBuckets is the number of buckets in the eviction age histogram, one per power of two.
+const ageBuckets = 64
+
+// ageBucket returns the histogram bucket for an age: bucket i holds ages in [2^i, 2^(i+1)).
+func ageBucket(age uint64) int {
+	if age == 0 {
+		return 0
+	}
+	return bits.Len64(age) - 1
+}
+
+// EvictionAgeHistogram returns how old entries were when the ring evicted them, measured in
+// Pushes between insertion and eviction (the evicting Push included). Bucket i counts
+// evictions with an age in [2^i, 2^(i+1)); with distinct keys a full ring of capacity n evicts
+// at age n. Only ring evictions are recorded, not Delete or Clear.
+// Returns nil unless the cache was created WithEvictionAgeHistogram.
+func (c *RingCache[K, V]) EvictionAgeHistogram() []uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.ageHist == nil {
+		return nil
+	}
+	return append([]uint64(nil), c.ageHist...)
+}
+
+// NextIndex returns the ring slot the next Push of a new key starts from: it writes there,
+// evicting the occupant if any, unless the slot holds a pinned key, in which case it moves
+// on to the following slots. Together with Entry's Slot it lets tooling predict evictions.
+func (c *RingCache[K, V]) NextIndex() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.next
+}
+
+// WouldEvict reports which entry a Push of key would evict right now, without changing anything.
+// It returns wouldEvict=false when key is already cached (re-pushing only moves it), when the
+// target slot is free, and when Push would store nothing at all (frozen cache, zero capacity,
+// or every slot pinned). The answer is only valid until the next write.
+func (c *RingCache[K, V]) WouldEvict(key K) (victimKey K, victimValue V, wouldEvict bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if _, ok := c.items[key]; ok || c.frozen || c.capacity == 0 {
+		return victimKey, victimValue, false
+	}
+	slot, ok := c.nextSlot()
+	if !ok || !c.occupied[slot] {
+		return victimKey, victimValue, false
+	}
+	if s, ok := c.expiredSlot(); ok {
+		slot = s
+	} else if c.score != nil {
+		slot = c.minScoreSlot()
+	}
+	victimKey = c.keys[slot]
+	return victimKey, c.items[victimKey], true
+}
+
+// UpcomingEvictions returns, oldest first, the cached keys that the next n Pushes of new,
+// distinct keys would evict given the current ring state, walking from NextIndex around the ring
+// and honoring pins and WithEvictBatch. Pushes that land in a free slot evict nothing and add
+// nothing to the result, so it may hold fewer than n keys; it never contains placeholders for
+// empty slots. The walk stops once it wraps around to slots those future Pushes would have
+// filled. Like WouldEvict the answer is a read-only prediction, valid until the next write.
+// Under WithScoreEvict the victims depend on the scores of entries not pushed yet, so it
+// returns nil. It ignores expiry: Pushes reclaim expired entries (PushWithTTL) first and may
+// therefore spare some of the keys listed.
+func (c *RingCache[K, V]) UpcomingEvictions(n int) []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if n <= 0 || c.frozen || c.capacity == 0 || c.score != nil {
+		return nil
+	}
+
+	const (
+		free = iota
+		cached
+		future // filled by one of the simulated Pushes
+	)
+	state := make([]uint8, c.capacity)
+	for i, occ := range c.occupied {
+		if occ {
+			state[i] = cached
+		}
+	}
+	evictable := func(s int) bool {
+		if state[s] != cached {
+			return state[s] == future
+		}
+		_, pinned := c.pinned[c.keys[s]]
+		return !pinned
+	}
+
+	var out []K
+	next := c.next
+	for p := 0; p < n; p++ {
+		slot := -1
+		for i := 0; i < c.capacity; i++ {
+			if s := (next + i) % c.capacity; state[s] == free || evictable(s) {
+				slot = s
+				break
+			}
+		}
+		if slot < 0 || state[slot] == future {
+			break
+		}
+		if state[slot] == cached {
+			out = append(out, c.keys[slot])
+			for i, evicted := 1, 1; i < c.capacity && evicted < c.evictBatch; i++ {
+				s := (slot + i) % c.capacity
+				if state[s] == free || !evictable(s) {
+					continue
+				}
+				if state[s] == cached {
+					out = append(out, c.keys[s])
+				}
+				state[s] = free
+				evicted++
+			}
+		}
+		state[slot] = future
+		next = (slot + 1) % c.capacity
+	}
+	return out
 }
 
-// Size returns the current number of items in the cache.
+// Pin protects an existing key from eviction when the ring wraps around.
+// Push skips slots holding pinned keys when choosing where to write; Delete and Clear
+// still remove pinned keys. Re-pushing a pinned key keeps it pinned.
+// If every slot ends up pinned, Push of a new key stores nothing and returns false
+// until a key is unpinned or deleted.
+// Returns false if the key is not in the cache.
+func (c *RingCache[K, V]) Pin(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[key]; !ok {
+		return false
+	}
+	c.pinned[key] = struct{}{}
+	return true
+}
+
+// Unpin makes a pinned key evictable again. Returns true if the key was pinned.
+func (c *RingCache[K, V]) Unpin(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pinned[key]; !ok {
+		return false
+	}
+	delete(c.pinned, key)
+	return true
+}
+
+// Compact closes the gaps left by Delete and similar removals: it moves the live entries, in
+// ring order (oldest first), into the contiguous slots [0, Size()) and points the next write at
+// Size() % Capacity(). Nothing is evicted and no callbacks fire; values, pins and sequence
+// numbers are kept, so only slot positions (Entry's Slot, NextIndex) change. Eviction order is
+// preserved. It runs in O(Capacity()) under the write lock and also works on a frozen cache.
+func (c *RingCache[K, V]) Compact() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.capacity == 0 {
+		return
+	}
+	live := c.entries()
+	var zeroK K
+	for i := range c.keys {
+		c.keys[i] = zeroK
+		c.occupied[i] = false
+	}
+	for i, e := range live {
+		c.keys[i] = e.Key
+		c.occupied[i] = true
+		c.pos[e.Key] = i
+	}
+	c.next = len(live) % c.capacity
+}
+
+// entries returns the live entries in ring order, oldest first: walking the slots
+// from next (the next write position, hence the oldest entry) around the ring.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) entries() []Entry[K, V] {
+	return c.appendEntries(make([]Entry[K, V], 0, len(c.items)))
+}
+
+// appendEntries appends the live entries to out in ring order, like entries.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) appendEntries(out []Entry[K, V]) []Entry[K, V] {
+	for i := 0; i < c.capacity; i++ {
+		s := (c.next + i) % c.capacity
+		if c.occupied[s] {
+			k := c.keys[s]
+			out = append(out, Entry[K, V]{Key: k, Value: c.items[k]})
+		}
+	}
+	return out
+}
+
+// nextSlot returns the slot the next Push writes into: c.next, or the first slot after it
+// that is free or holds an unpinned key. It reports false if every slot holds a pinned key.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) nextSlot() (int, bool) {
+	for i := 0; i < c.capacity; i++ {
+		s := (c.next + i) % c.capacity
+		if !c.occupied[s] {
+			return s, true
+		}
+		if _, pinned := c.pinned[c.keys[s]]; !pinned {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// Keys returns the cached keys in ring order from the next write position, oldest Push first,
+// read under the read lock. With plain Pushes that is the order of each key's last Push;
+// Touched and MoveToIndex change an entry's place in it. Evictions can depart from this order:
+// pinned keys are skipped, WithScoreEvict picks by score and a Push reclaims expired slots
+// first (see WouldEvict). Expired entries not removed yet are left out. The slice is a fresh
+// copy the caller may keep and modify.
+func (c *RingCache[K, V]) Keys() []K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]K, 0, len(c.items))
+	c.forEachLive(func(k K) { keys = append(keys, k) })
+	return keys
+}
+
+// Values returns the cached values in the order of Keys, as a fresh slice. Calling Keys and
+// Values separately may see different contents if the cache changes in between; use Entries
+// for a consistent pairing.
+func (c *RingCache[K, V]) Values() []V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	values := make([]V, 0, len(c.items))
+	c.forEachLive(func(k K) { values = append(values, c.items[k]) })
+	return values
+}
+
+// Entries returns the cached key/value pairs in the order of Keys, taken under a single read
+// lock, so the snapshot is consistent even with concurrent writers (e.g. for a debug dump).
+// The slice is a fresh copy.
+func (c *RingCache[K, V]) Entries() []Entry[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entries := make([]Entry[K, V], 0, len(c.items))
+	c.forEachLive(func(k K) { entries = append(entries, Entry[K, V]{Key: k, Value: c.items[k]}) })
+	return entries
+}
+
+// forEachLive calls f with every key that has not expired, in ring order, oldest first.
+// Caller must hold c.mu.
+func (c *RingCache[K, V]) forEachLive(f func(key K)) {
+	for i := 0; i < c.capacity; i++ {
+		s := (c.next + i) % c.capacity
+		if c.occupied[s] && !c.isExpired(c.keys[s]) {
+			f(c.keys[s])
+		}
+	}
+}
+
+// Range calls fn for every cached entry in ring order, oldest first, and stops early when fn
+// returns false, like sync.Map.Range. Unlike RangeErr it copies nothing: it holds the read lock
+// for the whole walk, so fn sees a consistent view but blocks writers while it runs. fn must
+// therefore be quick and must not call any method of the cache, not even a read: a nested read
+// lock deadlocks once a writer is waiting. Use RangeErr or Entries when fn needs the cache.
+// Expired entries not removed yet are skipped.
+func (c *RingCache[K, V]) Range(fn func(key K, value V) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := 0; i < c.capacity; i++ {
+		s := (c.next + i) % c.capacity
+		if !c.occupied[s] {
+			continue
+		}
+		k := c.keys[s]
+		if c.isExpired(k) {
+			continue
+		}
+		if !fn(k, c.items[k]) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the cached entries in ring order, oldest first, for use with
+// range-over-func:
+//
+//	for k, v := range rc.All() { ... }
+//
+// Each iteration copies the entries under the read lock first (see Entries) and yields from
+// that snapshot, so the loop body may call back into the cache and changes made meanwhile are
+// not seen. Breaking out of the loop stops early. The iterator can be reused; every use takes
+// a fresh snapshot. Keys already returns a slice: iterate over it directly, or pass
+// slices.Values(rc.Keys()) where an iter.Seq[K] is needed.
+func (c *RingCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, e := range c.Entries() {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// RangeErr calls f for every entry, oldest first, and stops at the first non-nil error,
+// which it returns. It iterates over a snapshot taken under the read lock, so f may do
+// arbitrary work, including calling back into the cache; changes made meanwhile are not seen.
+func (c *RingCache[K, V]) RangeErr(f func(key K, value V) error) error {
+	c.mu.RLock()
+	snap := c.entries()
+	c.mu.RUnlock()
+
+	for _, e := range snap {
+		if err := f(e.Key, e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RangeBatched calls f with successive batches of up to batchSize entries, walking the ring
+// oldest first, until f returns false or the ring is exhausted. Each batch is copied under the
+// read lock, which is released before f runs, so dumping a huge cache neither copies everything
+// up front nor blocks writers for the whole walk. A batchSize below 1 is treated as 1. f owns
+// each slice and may call back into the cache.
+//
+// The price is weaker consistency than RangeErr: each batch is a consistent view, but writes
+// between batches are visible. The walk follows ring slots from where the oldest entry was when
+// RangeBatched started, so an entry moved by a Push meanwhile may be seen twice or not at all,
+// and entries added behind the cursor are not seen. Expired entries not removed yet are skipped.
+func (c *RingCache[K, V]) RangeBatched(batchSize int, f func(batch []Entry[K, V]) bool) {
+	batchSize = max(batchSize, 1)
+	c.mu.RLock()
+	start := c.next
+	c.mu.RUnlock()
+
+	for off := 0; ; {
+		batch := make([]Entry[K, V], 0, batchSize)
+		c.mu.RLock()
+		for ; off < c.capacity && len(batch) < batchSize; off++ {
+			if s := (start + off) % c.capacity; c.occupied[s] && !c.isExpired(c.keys[s]) {
+				k := c.keys[s]
+				batch = append(batch, Entry[K, V]{Key: k, Value: c.items[k]})
+			}
+		}
+		done := off >= c.capacity
+		c.mu.RUnlock()
+
+		if len(batch) > 0 && !f(batch) {
+			return
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// SortedKeys returns all keys sorted by less, taken from a single read-locked snapshot.
+// An empty cache yields an empty, non-nil slice. Expired entries not removed yet are left out.
+func (c *RingCache[K, V]) SortedKeys(less func(a, b K) bool) []K {
+	c.mu.RLock()
+	keys := make([]K, 0, len(c.items))
+	c.forEachLive(func(k K) { keys = append(keys, k) })
+	c.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	return keys
+}
+
+// Sample returns up to n distinct entries chosen uniformly at random, without replacement
+// and regardless of where they sit in the ring. If n >= Size() every entry is returned,
+// in random order. Expired entries not removed yet are never chosen. Use WithRandSource for
+// deterministic sampling.
+func (c *RingCache[K, V]) Sample(n int) []Entry[K, V] {
+	c.mu.RLock()
+	snap := make([]Entry[K, V], 0, len(c.items))
+	c.forEachLive(func(k K) { snap = append(snap, Entry[K, V]{Key: k, Value: c.items[k]}) })
+	c.mu.RUnlock()
+
+	n = min(max(n, 0), len(snap))
+	// Partial Fisher-Yates: after i steps, snap[:i] is a uniform sample.
+	for i := 0; i < n; i++ {
+		j := i + c.intN(len(snap)-i)
+		snap[i], snap[j] = snap[j], snap[i]
+	}
+	return snap[:n]
+}
+
+// intN returns a random int in [0, n) from the cache's generator.
+func (c *RingCache[K, V]) intN(n int) int {
+	if c.rng == nil {
+		return rand.IntN(n)
+	}
+	c.rngMu.Lock()
+	defer c.rngMu.Unlock()
+	return c.rng.IntN(n)
+}
+
+// Size returns the current number of items in the cache. Expired entries (PushWithTTL,
+// WithDefaultTTL) are counted until they are removed, see DeleteExpired.
+// It reads an atomic counter kept in step with every mutation and never blocks on writers.
 func (c *RingCache[K, V]) Size() int {
+	return int(c.size.Load())
+}
+
+// Free returns how many more keys fit before a Push has to evict (Capacity() - Size()).
+// It reads both under a single lock acquisition; returns 0 when the cache is full.
+func (c *RingCache[K, V]) Free() int {
 	c.mu.RLock()
-	n := len(c.items)
+	n := c.capacity - len(c.items)
 	c.mu.RUnlock()
 	return n
 }
 
-// Capacity returns the fixed capacity of the cache.
+// Capacity returns the capacity of the cache. It only changes when contents are exchanged with Swap.
 func (c *RingCache[K, V]) Capacity() int {
-	// Immutable after construction; no lock required.
-	return c.capacity
+	c.mu.RLock()
+	n := c.capacity
+	c.mu.RUnlock()
+	return n
+}
+
+// SizeAndCapacity returns Size() and Capacity() read under a single lock acquisition, so the
+// pair is consistent even while Swap exchanges the capacity; use it to compute utilization.
+func (c *RingCache[K, V]) SizeAndCapacity() (size, capacity int) {
+	c.mu.RLock()
+	size, capacity = len(c.items), c.capacity
+	c.mu.RUnlock()
+	return size, capacity
 }
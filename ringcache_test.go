@@ -2,6 +2,10 @@
 package ringcache_test
 
 import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -301,6 +305,60 @@ func TestClearWithNoCallback(t *testing.T) {
 	}
 }
 
+func TestEvictCallbacksCompleteBeforePushReturns(t *testing.T) {
+	// Side effects are written without synchronization on purpose: the contract is that the
+	// callbacks have fully run on the calling goroutine by the time Push returns.
+	for _, tc := range []struct {
+		name string
+		opts func(log *[]string) []ringcache.Option[int, string]
+	}{
+		{"per-entry", func(log *[]string) []ringcache.Option[int, string] {
+			return []ringcache.Option[int, string]{
+				ringcache.WithEvictCallback[int, string](func(_ int, v string) { *log = append(*log, v) }),
+			}
+		}},
+		{"per-entry recovered", func(log *[]string) []ringcache.Option[int, string] {
+			return []ringcache.Option[int, string]{
+				ringcache.WithEvictCallback[int, string](func(_ int, v string) { *log = append(*log, v) }),
+				ringcache.WithRecoverCallbacks[int, string](),
+			}
+		}},
+		{"batch", func(log *[]string) []ringcache.Option[int, string] {
+			return []ringcache.Option[int, string]{
+				ringcache.WithBatchEvictCallback[int, string](func(es []ringcache.Entry[int, string]) {
+					for _, e := range es {
+						*log = append(*log, e.Value)
+					}
+				}),
+			}
+		}},
+		{"write-back", func(log *[]string) []ringcache.Option[int, string] {
+			return []ringcache.Option[int, string]{
+				ringcache.WithWriteBack[int, string](1, func(es []ringcache.Entry[int, string]) error {
+					for _, e := range es {
+						*log = append(*log, e.Value)
+					}
+					return nil
+				}),
+			}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var log []string
+			rc, _ := ringcache.New[int, string](1, tc.opts(&log)...)
+			rc.Push(1, "one")
+			rc.Push(2, "two")
+			if len(log) != 1 || log[0] != "one" {
+				t.Fatalf("after Push returned: log = %v, want [one]", log)
+			}
+			rc.Delete(2)
+			if len(log) != 2 || log[1] != "two" {
+				t.Fatalf("after Delete returned: log = %v, want [one two]", log)
+			}
+		})
+	}
+}
+
 func TestPushEvictionWithNoCallback(t *testing.T) {
 	rc, _ := ringcache.New[int, string](1)
 	rc.Push(1, "a")
@@ -316,3 +374,1446 @@ func TestPushEvictionWithNoCallback(t *testing.T) {
 		t.Fatalf("key 2 should be present")
 	}
 }
+
+func TestPin_SkipsPinnedSlot(t *testing.T) {
+	var evicted []int
+	cb := func(k int, _ string) { evicted = append(evicted, k) }
+	rc, _ := ringcache.NewWithEvictCallback[int, string](3, cb)
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	rc.Push(3, "three")
+
+	if !rc.Pin(1) {
+		t.Fatalf("expected Pin(1)=true")
+	}
+
+	// Slot of 1 is next in line; the write must skip it and evict 2 instead.
+	if !rc.Push(4, "four") {
+		t.Fatalf("expected eviction on pushing 4")
+	}
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("evicted = %v, want [2]", evicted)
+	}
+	if !rc.Has(1) || !rc.Has(3) || !rc.Has(4) {
+		t.Fatalf("expected 1, 3 and 4 to exist")
+	}
+
+	// After unpinning, 1 is evictable again once the ring comes back around.
+	if !rc.Unpin(1) {
+		t.Fatalf("expected Unpin(1)=true")
+	}
+	rc.Push(5, "five") // evicts 3
+	rc.Push(6, "six")  // evicts 1
+	if rc.Has(1) {
+		t.Fatalf("1 should be evicted after Unpin")
+	}
+}
+
+func TestPin_AllPinnedRejectsNewKey(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	rc.Pin(1)
+	rc.Pin(2)
+
+	if rc.Push(3, "three") {
+		t.Fatalf("push into a fully pinned cache should not evict")
+	}
+	if rc.Has(3) {
+		t.Fatalf("3 should not be stored when every slot is pinned")
+	}
+
+	// Re-pushing a pinned key still works and keeps it pinned.
+	rc.Push(1, "uno")
+	if v, _ := rc.Load(1); v != "uno" {
+		t.Fatalf("1 value mismatch: got %q, want %q", v, "uno")
+	}
+	if rc.Push(3, "three") || rc.Has(3) {
+		t.Fatalf("1 should still be pinned after re-push")
+	}
+}
+
+func TestPin_MissingKeyAndDelete(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	if rc.Pin(1) {
+		t.Fatalf("expected Pin on missing key to return false")
+	}
+	if rc.Unpin(1) {
+		t.Fatalf("expected Unpin on unpinned key to return false")
+	}
+
+	rc.Push(1, "one")
+	rc.Pin(1)
+	if !rc.Delete(1) {
+		t.Fatalf("pinned key should still be deletable")
+	}
+	if rc.Unpin(1) {
+		t.Fatalf("Delete should drop the pin")
+	}
+}
+
+func TestFree(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	if rc.Free() != 2 {
+		t.Fatalf("free on empty cache: got %d, want 2", rc.Free())
+	}
+	rc.Push(1, "a")
+	if rc.Free() != 1 {
+		t.Fatalf("free after one push: got %d, want 1", rc.Free())
+	}
+	rc.Push(2, "b")
+	rc.Push(3, "c")
+	if rc.Free() != 0 {
+		t.Fatalf("free on full cache: got %d, want 0", rc.Free())
+	}
+}
+
+func TestSequence(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2, ringcache.WithSequence[int, string]())
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	rc.Push(3, "three") // evicts 1, wraps the ring
+
+	s2, ok2 := rc.Sequence(2)
+	s3, ok3 := rc.Sequence(3)
+	if !ok2 || !ok3 || s2 != 2 || s3 != 3 {
+		t.Fatalf("sequences = (%d,%v) (%d,%v), want (2,true) (3,true)", s2, ok2, s3, ok3)
+	}
+	if _, ok := rc.Sequence(1); ok {
+		t.Fatalf("evicted key should have no sequence")
+	}
+
+	// Re-push takes a fresh sequence number.
+	rc.Push(2, "deux")
+	if s, _ := rc.Sequence(2); s != 4 {
+		t.Fatalf("sequence after re-push = %d, want 4", s)
+	}
+
+	// Numbers are never reused, even after Clear.
+	rc.Clear()
+	rc.Push(5, "five")
+	if s, _ := rc.Sequence(5); s != 5 {
+		t.Fatalf("sequence after clear = %d, want 5", s)
+	}
+}
+
+func TestSequence_Disabled(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	rc.Push(1, "one")
+	if _, ok := rc.Sequence(1); ok {
+		t.Fatalf("Sequence should report false without WithSequence")
+	}
+}
+
+func TestPeek_NoSideEffects(t *testing.T) {
+	var hooks int
+	rc, _ := ringcache.New[int, string](2,
+		ringcache.WithOnHit[int, string](func(int, string) { hooks++ }),
+		ringcache.WithOnMiss[int, string](func(int) { hooks++ }))
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	gen := rc.Generation()
+
+	if v, ok := rc.Peek(1); !ok || v != "one" {
+		t.Fatalf("Peek(1) = %q, %v", v, ok)
+	}
+	if _, ok := rc.Peek(9); ok {
+		t.Fatal("Peek(9) found a missing key")
+	}
+	if p, ok := rc.PeekPos(2); !ok || p != 1 {
+		t.Fatalf("PeekPos(2) = %d, %v; want 1, true", p, ok)
+	}
+	if p, ok := rc.PeekPos(9); ok || p != -1 {
+		t.Fatalf("PeekPos(9) = %d, %v; want -1, false", p, ok)
+	}
+	if st := rc.Stats(); st.Hits != 0 || st.Misses != 0 || hooks != 0 || rc.Generation() != gen {
+		t.Fatalf("Peek had side effects: stats %+v, hooks %d", st, hooks)
+	}
+	// Key 1 is still the oldest.
+	if k, _, ok := rc.PushEvicting(3, "three"); !ok || k != 1 {
+		t.Fatalf("evicted %d, %v; want 1", k, ok)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, string](3, ringcache.WithClock[int, string](clk.now))
+	if keys := rc.Keys(); keys == nil || len(keys) != 0 {
+		t.Fatalf("Keys of an empty cache = %#v, want an empty slice", keys)
+	}
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, "v") // evicts 1
+	}
+	rc.Push(2, "v2") // moves 2 to the newest position
+	keys := rc.Keys()
+	if !slices.Equal(keys, []int{3, 4, 2}) {
+		t.Fatalf("Keys = %v, want [3 4 2]", keys)
+	}
+	keys[0] = 99
+	if again := rc.Keys(); again[0] != 3 {
+		t.Fatal("Keys must return a copy")
+	}
+	rc.PushWithTTL(5, "v", time.Minute)
+	if keys := rc.Keys(); !slices.Equal(keys, []int{4, 2, 5}) {
+		t.Fatalf("Keys = %v, want [4 2 5]", keys)
+	}
+	clk.advance(time.Minute)
+	if keys := rc.Keys(); !slices.Equal(keys, []int{4, 2}) {
+		t.Fatalf("Keys = %v, want the expired 5 left out", keys)
+	}
+}
+
+func TestValuesAndEntries(t *testing.T) {
+	rc, _ := ringcache.New[int, string](3)
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, fmt.Sprint("v", i))
+	}
+	if vals := rc.Values(); !slices.Equal(vals, []string{"v2", "v3", "v4"}) {
+		t.Fatalf("Values = %v, want [v2 v3 v4]", vals)
+	}
+	want := []ringcache.Entry[int, string]{{Key: 2, Value: "v2"}, {Key: 3, Value: "v3"}, {Key: 4, Value: "v4"}}
+	if got := rc.Entries(); !slices.Equal(got, want) {
+		t.Fatalf("Entries = %v, want %v", got, want)
+	}
+}
+
+func TestEntries_ConsistentUnderConcurrentPush(t *testing.T) {
+	rc, _ := ringcache.New[int, int](64)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5000; i++ {
+			rc.Push(i%100, i%100)
+		}
+	}()
+	for {
+		for _, e := range rc.Entries() {
+			if e.Key != e.Value {
+				t.Fatalf("inconsistent entry %v", e)
+			}
+		}
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	rc, _ := ringcache.New[int, int](4)
+	for i := 1; i <= 5; i++ {
+		rc.Push(i, i*10)
+	}
+	var keys []int
+	sum := 0
+	rc.Range(func(k, v int) bool {
+		keys = append(keys, k)
+		sum += v
+		return true
+	})
+	if !slices.Equal(keys, []int{2, 3, 4, 5}) || sum != 140 {
+		t.Fatalf("Range visited %v (sum %d), want [2 3 4 5] (sum 140)", keys, sum)
+	}
+
+	keys = keys[:0]
+	rc.Range(func(k, _ int) bool {
+		keys = append(keys, k)
+		return k != 3
+	})
+	if !slices.Equal(keys, []int{2, 3}) {
+		t.Fatalf("Range did not stop early: %v", keys)
+	}
+}
+
+func TestRange_ZeroAllocs(t *testing.T) {
+	rc, _ := ringcache.New[int, int](64)
+	for i := 0; i < 64; i++ {
+		rc.Push(i, i)
+	}
+	n := 0
+	fn := func(int, int) bool { n++; return true }
+	if allocs := testing.AllocsPerRun(100, func() { rc.Range(fn) }); allocs != 0 {
+		t.Fatalf("Range allocated %.1f times per run, want 0", allocs)
+	}
+}
+
+func TestAll(t *testing.T) {
+	rc, _ := ringcache.New[int, string](3)
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, fmt.Sprint("v", i))
+	}
+	var got []ringcache.Entry[int, string]
+	for k, v := range rc.All() {
+		got = append(got, ringcache.Entry[int, string]{Key: k, Value: v})
+		rc.Delete(k) // the loop body may call back into the cache
+	}
+	want := []ringcache.Entry[int, string]{{Key: 2, Value: "v2"}, {Key: 3, Value: "v3"}, {Key: 4, Value: "v4"}}
+	if !slices.Equal(got, want) || rc.Size() != 0 {
+		t.Fatalf("All yielded %v (size after %d), want %v", got, rc.Size(), want)
+	}
+
+	rc.Push(1, "a")
+	rc.Push(2, "b")
+	n := 0
+	for range rc.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatalf("break did not stop the iteration: %d", n)
+	}
+	if keys := slices.Collect(slices.Values(rc.Keys())); !slices.Equal(keys, []int{1, 2}) {
+		t.Fatalf("keys = %v", keys)
+	}
+}
+
+func TestOldestAge(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2, ringcache.WithInsertTimestamps[int, string]())
+	if _, ok := rc.OldestAge(); ok {
+		t.Fatal("OldestAge should report false for an empty cache")
+	}
+	rc.Push(1, "one")
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	rc.Push(2, "two")
+	if age, ok := rc.OldestAge(); !ok || age < 20*time.Millisecond {
+		t.Fatalf("OldestAge = %v, %v; want at least 20ms", age, ok)
+	}
+	rc.Push(3, "three") // evicts 1, the oldest
+	if age, ok := rc.OldestAge(); !ok || age > time.Since(start) {
+		t.Fatalf("OldestAge = %v, %v; want the age of key 2 (<= %v)", age, ok, time.Since(start))
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+	rc.Clear()
+	if _, ok := rc.OldestAge(); ok {
+		t.Fatal("OldestAge should report false after Clear")
+	}
+}
+
+func TestOldestAge_Disabled(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	rc.Push(1, "one")
+	if _, ok := rc.OldestAge(); ok {
+		t.Fatalf("OldestAge should report false without WithInsertTimestamps")
+	}
+}
+
+func TestGeneration(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	g := rc.Generation()
+
+	changed := func(name string) {
+		t.Helper()
+		if ng := rc.Generation(); ng <= g {
+			t.Fatalf("%s: generation did not increase (%d -> %d)", name, g, ng)
+		} else {
+			g = ng
+		}
+	}
+	unchanged := func(name string) {
+		t.Helper()
+		if ng := rc.Generation(); ng != g {
+			t.Fatalf("%s: generation changed unexpectedly (%d -> %d)", name, g, ng)
+		}
+	}
+
+	rc.Push(1, "one")
+	changed("push")
+	rc.Pin(1)
+	unchanged("pin")
+	rc.Delete(99)
+	unchanged("delete of missing key")
+	rc.Delete(1)
+	changed("delete")
+	rc.Clear()
+	unchanged("clear of empty cache")
+	rc.Push(2, "two")
+	changed("push")
+	rc.Clear()
+	changed("clear")
+	_ = rc.Size()
+	_, _ = rc.Load(2)
+	unchanged("reads")
+}
+
+func TestSetValue(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	if err := rc.SetValue(1, "x"); !errors.Is(err, ringcache.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	if rc.Has(1) {
+		t.Fatalf("SetValue must not insert missing keys")
+	}
+
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	if err := rc.SetValue(1, "uno"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, _ := rc.Load(1); v != "uno" {
+		t.Fatalf("value mismatch: got %q, want %q", v, "uno")
+	}
+
+	// No promotion: 1 is still the oldest entry and goes first.
+	rc.Push(3, "three")
+	if rc.Has(1) {
+		t.Fatalf("SetValue should not move the key in the ring")
+	}
+}
+
+func TestRangeErr(t *testing.T) {
+	rc, _ := ringcache.New[int, string](3)
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	rc.Push(3, "three")
+
+	var seen []int
+	if err := rc.RangeErr(func(k int, _ string) error {
+		seen = append(seen, k)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 3 || seen[0] != 1 || seen[1] != 2 || seen[2] != 3 {
+		t.Fatalf("visited %v, want [1 2 3]", seen)
+	}
+
+	errStop := errors.New("stop")
+	seen = seen[:0]
+	err := rc.RangeErr(func(k int, _ string) error {
+		seen = append(seen, k)
+		if k == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected errStop, got %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("expected iteration to stop after 2 entries, visited %v", seen)
+	}
+}
+
+func TestRangeErr_CallbackMayMutate(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+
+	// Works over a snapshot, so deleting inside f must not deadlock.
+	_ = rc.RangeErr(func(k int, _ string) error {
+		rc.Delete(k)
+		return nil
+	})
+	if rc.Size() != 0 {
+		t.Fatalf("size after deleting inside RangeErr = %d, want 0", rc.Size())
+	}
+}
+
+func TestSample(t *testing.T) {
+	newCache := func() *ringcache.RingCache[int, int] {
+		rc, _ := ringcache.New[int, int](10, ringcache.WithRandSource[int, int](rand.NewPCG(1, 2)))
+		for i := 0; i < 10; i++ {
+			rc.Push(i, i*10)
+		}
+		return rc
+	}
+
+	rc := newCache()
+	got := rc.Sample(4)
+	if len(got) != 4 {
+		t.Fatalf("sample size = %d, want 4", len(got))
+	}
+	seen := map[int]bool{}
+	for _, e := range got {
+		if seen[e.Key] {
+			t.Fatalf("duplicate key %d in sample", e.Key)
+		}
+		seen[e.Key] = true
+		if e.Value != e.Key*10 {
+			t.Fatalf("sampled pair (%d,%d) does not match cache contents", e.Key, e.Value)
+		}
+	}
+
+	// Same seed, same sample.
+	again := newCache().Sample(4)
+	for i := range got {
+		if got[i] != again[i] {
+			t.Fatalf("seeded samples differ: %v vs %v", got, again)
+		}
+	}
+
+	if all := rc.Sample(100); len(all) != 10 {
+		t.Fatalf("oversized sample = %d entries, want 10", len(all))
+	}
+	if none := rc.Sample(0); len(none) != 0 {
+		t.Fatalf("Sample(0) = %v, want empty", none)
+	}
+}
+
+func TestSample_Unseeded(t *testing.T) {
+	rc, _ := ringcache.New[int, int](3)
+	rc.Push(1, 1)
+	rc.Push(2, 2)
+	if got := rc.Sample(1); len(got) != 1 || !rc.Has(got[0].Key) {
+		t.Fatalf("unexpected sample %v", got)
+	}
+}
+
+func TestPushEvicting(t *testing.T) {
+	var calls int32
+	cb := func(_ int, _ string) { atomic.AddInt32(&calls, 1) }
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, cb)
+
+	if _, _, ev := rc.PushEvicting(1, "one"); ev {
+		t.Fatalf("unexpected eviction on first push")
+	}
+	k, v, ev := rc.PushEvicting(2, "two")
+	if !ev || k != 1 || v != "one" {
+		t.Fatalf("PushEvicting = (%d,%q,%v), want (1,\"one\",true)", k, v, ev)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("callback should still fire, got %d calls", calls)
+	}
+}
+
+func TestSize_TracksEveryMutationPath(t *testing.T) {
+	rc, _ := ringcache.New[int, string](3)
+	steps := []struct {
+		name string
+		op   func()
+		want int
+	}{
+		{"push new", func() { rc.Push(1, "a") }, 1},
+		{"push existing", func() { rc.Push(1, "b") }, 1},
+		{"push second", func() { rc.Push(2, "c") }, 2},
+		{"push third", func() { rc.Push(3, "d") }, 3},
+		{"push evicting", func() { rc.Push(4, "e") }, 3},
+		{"delete", func() { rc.Delete(4) }, 2},
+		{"delete missing", func() { rc.Delete(4) }, 2},
+		{"clear", func() { rc.Clear() }, 0},
+	}
+	for _, s := range steps {
+		s.op()
+		if got := rc.Size(); got != s.want {
+			t.Fatalf("%s: size = %d, want %d", s.name, got, s.want)
+		}
+		if err := rc.Healthy(); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+	}
+}
+
+func TestCompositeKeys(t *testing.T) {
+	rc, _ := ringcache.New[ringcache.Key2[string, string], int](4)
+
+	// These collide when joined as "a:b" strings, but not as Key2.
+	rc.Push(ringcache.Key2[string, string]{A: "a:b", B: "c"}, 1)
+	rc.Push(ringcache.Key2[string, string]{A: "a", B: "b:c"}, 2)
+
+	if rc.Size() != 2 {
+		t.Fatalf("size = %d, want 2 distinct keys", rc.Size())
+	}
+	if v, ok := rc.Load(ringcache.Key2[string, string]{A: "a", B: "b:c"}); !ok || v != 2 {
+		t.Fatalf("Load = (%d,%v), want (2,true)", v, ok)
+	}
+
+	rc3, _ := ringcache.New[ringcache.Key3[string, int, bool], string](1)
+	rc3.Push(ringcache.Key3[string, int, bool]{A: "x", B: 1, C: true}, "v")
+	if !rc3.Has(ringcache.Key3[string, int, bool]{A: "x", B: 1, C: true}) {
+		t.Fatalf("expected Key3 lookup to succeed")
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	var calls int32
+	cb := func(_ int, _ string) { atomic.AddInt32(&calls, 1) }
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, cb)
+	rc.Push(1, "one")
+
+	rc.Freeze()
+	if !rc.Frozen() {
+		t.Fatalf("expected Frozen()=true")
+	}
+	if rc.Push(2, "two") || rc.Has(2) {
+		t.Fatalf("Push while frozen must be a no-op")
+	}
+	if rc.Delete(1) {
+		t.Fatalf("Delete while frozen must return false")
+	}
+	rc.Clear()
+	if err := rc.SetValue(1, "uno"); !errors.Is(err, ringcache.ErrFrozen) {
+		t.Fatalf("SetValue while frozen: got %v, want ErrFrozen", err)
+	}
+	if v, ok := rc.Load(1); !ok || v != "one" {
+		t.Fatalf("reads must keep working while frozen, got (%q,%v)", v, ok)
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Fatalf("no callbacks expected while frozen, got %d", calls)
+	}
+
+	rc.Unfreeze()
+	if rc.Frozen() {
+		t.Fatalf("expected Frozen()=false after Unfreeze")
+	}
+	if !rc.Push(2, "two") {
+		t.Fatalf("expected eviction after Unfreeze")
+	}
+}
+
+func TestZeroCapacityPassthrough(t *testing.T) {
+	if _, err := ringcache.New[int, string](0); err == nil {
+		t.Fatalf("capacity 0 must still be rejected by default")
+	}
+	if _, err := ringcache.New[int, string](-1, ringcache.WithAllowZeroCapacity[int, string]()); err == nil {
+		t.Fatalf("negative capacity must be rejected even with WithAllowZeroCapacity")
+	}
+
+	rc, err := ringcache.New[int, string](0, ringcache.WithAllowZeroCapacity[int, string]())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc.Push(1, "one") {
+		t.Fatalf("pass-through Push must not report eviction")
+	}
+	if _, ok := rc.Load(1); ok || rc.Has(1) {
+		t.Fatalf("pass-through cache must not store anything")
+	}
+	if rc.Size() != 0 || rc.Capacity() != 0 || rc.Free() != 0 {
+		t.Fatalf("unexpected size/capacity/free: %d/%d/%d", rc.Size(), rc.Capacity(), rc.Free())
+	}
+	rc.Delete(1)
+	rc.Clear()
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("pass-through cache should be healthy: %v", err)
+	}
+}
+
+func TestEntryInfo(t *testing.T) {
+	rc, _ := ringcache.New[int, string](3, ringcache.WithSequence[int, string]())
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	rc.Pin(2)
+
+	info, ok := rc.Entry(2)
+	if !ok {
+		t.Fatalf("expected Entry(2) to exist")
+	}
+	want := ringcache.EntryInfo[string]{Value: "two", Slot: 1, Sequence: 2, Pinned: true}
+	if info != want {
+		t.Fatalf("Entry(2) = %+v, want %+v", info, want)
+	}
+	if _, ok := rc.Entry(3); ok {
+		t.Fatalf("expected Entry(3) to be absent")
+	}
+
+	plain, _ := ringcache.New[int, string](1)
+	plain.Push(1, "one")
+	if info, _ := plain.Entry(1); info.Sequence != 0 {
+		t.Fatalf("Sequence should be zero without WithSequence, got %d", info.Sequence)
+	}
+	if info, _ := plain.Entry(1); !info.InsertedAt.IsZero() {
+		t.Fatalf("InsertedAt should be zero without WithInsertTimestamps, got %v", info.InsertedAt)
+	}
+
+	stamped, _ := ringcache.New[int, string](1, ringcache.WithInsertTimestamps[int, string]())
+	before := time.Now()
+	stamped.Push(1, "one")
+	if info, _ := stamped.Entry(1); info.InsertedAt.Before(before) || info.InsertedAt.After(time.Now()) {
+		t.Fatalf("InsertedAt = %v, want the time of the Push", info.InsertedAt)
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	rc, _ := ringcache.New[int, string](4)
+	if keys := rc.SortedKeys(func(a, b int) bool { return a < b }); keys == nil || len(keys) != 0 {
+		t.Fatalf("empty cache: got %v, want empty non-nil slice", keys)
+	}
+
+	for _, k := range []int{3, 1, 4, 2} {
+		rc.Push(k, "v")
+	}
+	keys := rc.SortedKeys(func(a, b int) bool { return a > b })
+	want := []int{4, 3, 2, 1}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("SortedKeys = %v, want %v", keys, want)
+		}
+	}
+}
+
+func TestRecoverCallbacks(t *testing.T) {
+	var recovered []any
+	cb := func(k int, _ string) { panic(k) }
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, cb,
+		ringcache.WithPanicHandler[int, string](func(r any) { recovered = append(recovered, r) }))
+
+	rc.Push(1, "one")
+	if !rc.Push(2, "two") {
+		t.Fatalf("expected eviction")
+	}
+	if !rc.Delete(2) {
+		t.Fatalf("expected Delete(2)=true")
+	}
+	if len(recovered) != 2 || recovered[0] != 1 || recovered[1] != 2 {
+		t.Fatalf("recovered = %v, want [1 2]", recovered)
+	}
+}
+
+func TestRecoverCallbacks_WithoutHandler(t *testing.T) {
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, func(int, string) { panic("boom") },
+		ringcache.WithRecoverCallbacks[int, string]())
+	rc.Push(1, "one")
+	rc.Push(2, "two") // must not panic
+	if !rc.Has(2) {
+		t.Fatalf("push should complete despite the panicking callback")
+	}
+}
+
+func TestRecoverCallbacks_ClearContinues(t *testing.T) {
+	var recovered []any
+	var called []int
+	cb := func(k int, _ string) {
+		called = append(called, k)
+		if k == 2 {
+			panic("flaky sink")
+		}
+	}
+	rc, _ := ringcache.NewWithEvictCallback[int, string](4, cb,
+		ringcache.WithOrderedClear[int, string](),
+		ringcache.WithPanicHandler[int, string](func(r any) { recovered = append(recovered, r) }))
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, "v")
+	}
+
+	rc.Clear()
+	if !slices.Equal(called, []int{1, 2, 3, 4}) {
+		t.Fatalf("callbacks = %v, want all four entries", called)
+	}
+	if len(recovered) != 1 || recovered[0] != "flaky sink" {
+		t.Fatalf("recovered = %v, want one panic", recovered)
+	}
+	if rc.Size() != 0 {
+		t.Fatalf("size = %d after Clear", rc.Size())
+	}
+}
+
+func TestClear_PanicWithoutRecoverLeavesCacheEmpty(t *testing.T) {
+	rc, _ := ringcache.NewWithEvictCallback[int, string](2, func(int, string) { panic("boom") })
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("expected panic to propagate, got %v", r)
+			}
+		}()
+		rc.Clear()
+	}()
+	if rc.Size() != 0 || rc.Has(1) || rc.Has(2) {
+		t.Fatalf("cache not cleared after a callback panic: size %d", rc.Size())
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallbackPanicPropagatesByDefault(t *testing.T) {
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, func(int, string) { panic("boom") })
+	rc.Push(1, "one")
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("expected panic to propagate, got %v", r)
+		}
+	}()
+	rc.Push(2, "two")
+}
+
+func TestCallbackPanicPropagatesWithTimeout(t *testing.T) {
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, func(int, string) { panic("boom") },
+		ringcache.WithCallbackTimeout[int, string](time.Second))
+	rc.Push(1, "one")
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("expected panic to propagate to the caller, got %v", r)
+		}
+	}()
+	rc.Push(2, "two")
+}
+
+// A callback that panics after the caller stopped waiting must not crash the process.
+func TestCallbackPanicAfterTimeoutIsDiscarded(t *testing.T) {
+	release, finished := make(chan struct{}), make(chan struct{})
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, func(int, string) {
+		defer close(finished)
+		<-release
+		panic("late")
+	}, ringcache.WithCallbackTimeout[int, string](time.Millisecond))
+	rc.Push(1, "one")
+	rc.Push(2, "two") // returns once the callback times out
+	if got := rc.Stats().CallbackTimeouts; got != 1 {
+		t.Fatalf("CallbackTimeouts = %d, want 1", got)
+	}
+	close(release)
+	<-finished
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestWithSeed_Deterministic(t *testing.T) {
+	sample := func(seed uint64) []ringcache.Entry[int, int] {
+		rc, _ := ringcache.New[int, int](32, ringcache.WithSeed[int, int](seed))
+		for i := 0; i < 32; i++ {
+			rc.Push(i, i)
+		}
+		return rc.Sample(8)
+	}
+	a, b := sample(7), sample(7)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed produced different samples: %v vs %v", a, b)
+		}
+	}
+}
+
+func TestNextIndex(t *testing.T) {
+	rc, _ := ringcache.New[int, string](3)
+	if rc.NextIndex() != 0 {
+		t.Fatalf("initial next = %d, want 0", rc.NextIndex())
+	}
+	rc.Push(1, "a")
+	rc.Push(2, "b")
+	if rc.NextIndex() != 2 {
+		t.Fatalf("next = %d, want 2", rc.NextIndex())
+	}
+	rc.Push(3, "c")
+	if rc.NextIndex() != 0 {
+		t.Fatalf("next should wrap to 0, got %d", rc.NextIndex())
+	}
+	if info, _ := rc.Entry(1); info.Slot != rc.NextIndex() {
+		t.Fatalf("oldest entry should sit at the next write slot")
+	}
+}
+
+func TestBatchEvictCallback(t *testing.T) {
+	var order []string
+	var batches [][]ringcache.Entry[int, string]
+	cb := func(k int, _ string) { order = append(order, fmt.Sprintf("entry:%d", k)) }
+	rc, _ := ringcache.NewWithEvictCallback[int, string](3, cb,
+		ringcache.WithBatchEvictCallback[int, string](func(es []ringcache.Entry[int, string]) {
+			order = append(order, fmt.Sprintf("batch:%d", len(es)))
+			batches = append(batches, es)
+		}))
+
+	rc.Push(1, "a")
+	rc.Push(2, "b")
+	rc.Delete(1)
+	if len(batches) != 1 || len(batches[0]) != 1 || batches[0][0].Key != 1 {
+		t.Fatalf("Delete batch = %v, want [[1]]", batches)
+	}
+
+	rc.Push(3, "c")
+	rc.Clear()
+	if len(batches) != 2 || len(batches[1]) != 2 {
+		t.Fatalf("Clear should deliver one batch of 2, got %v", batches)
+	}
+	// Per-entry callbacks run before the batch callback.
+	if order[0] != "entry:1" || order[1] != "batch:1" || order[len(order)-1] != "batch:2" {
+		t.Fatalf("callback order = %v", order)
+	}
+
+	empty, _ := ringcache.New[int, string](1, ringcache.WithBatchEvictCallback[int, string](
+		func([]ringcache.Entry[int, string]) { t.Fatalf("no batch expected for an empty Clear") }))
+	empty.Clear()
+}
+
+func TestTouched(t *testing.T) {
+	rc, _ := ringcache.New[int, string](3)
+	if rc.Touched(1) {
+		t.Fatalf("Touched on missing key should return false")
+	}
+	if rc.Has(1) {
+		t.Fatalf("Touched must not insert")
+	}
+
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	rc.Push(3, "three")
+	// next points at 1's slot, so promoting 1 rewrites it there without evicting;
+	// afterwards 2 is the oldest entry.
+	if !rc.Touched(1) {
+		t.Fatalf("Touched(1) should return true")
+	}
+	if rc.Size() != 3 {
+		t.Fatalf("size = %d, want 3", rc.Size())
+	}
+	rc.Push(4, "four")
+	if rc.Has(2) || !rc.Has(1) {
+		t.Fatalf("2 should be evicted before the promoted 1")
+	}
+	if v, _ := rc.Load(1); v != "one" {
+		t.Fatalf("Touched must keep the value, got %q", v)
+	}
+}
+
+func TestLoadAndTouch(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	if _, ok := rc.LoadAndTouch(1); ok {
+		t.Fatalf("LoadAndTouch on missing key should miss")
+	}
+
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	v, ok := rc.LoadAndTouch(1)
+	if !ok || v != "one" {
+		t.Fatalf("LoadAndTouch(1) = %q, %v; want one, true", v, ok)
+	}
+	rc.Push(3, "three")
+	if rc.Has(2) || !rc.Has(1) {
+		t.Fatalf("2 should be evicted before the promoted 1")
+	}
+}
+
+func TestEvictSlot(t *testing.T) {
+	var evicted []int
+	rc, _ := ringcache.NewWithEvictCallback[int, string](3, func(k int, _ string) {
+		evicted = append(evicted, k)
+	})
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+
+	for _, idx := range []int{-1, 3, 2} {
+		if _, _, ok := rc.EvictSlot(idx); ok {
+			t.Fatalf("EvictSlot(%d) should report false", idx)
+		}
+	}
+	k, v, ok := rc.EvictSlot(1)
+	if !ok || k != 2 || v != "two" {
+		t.Fatalf("EvictSlot(1) = %d, %q, %v; want 2, two, true", k, v, ok)
+	}
+	if rc.Has(2) || rc.Size() != 1 {
+		t.Fatalf("key 2 should be gone, size = %d", rc.Size())
+	}
+	if len(evicted) != 1 || evicted[0] != 2 {
+		t.Fatalf("callback saw %v, want [2]", evicted)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("cache unhealthy after EvictSlot: %v", err)
+	}
+}
+
+func TestWithEvictBatch(t *testing.T) {
+	var batches [][]int
+	rc, _ := ringcache.New[int, int](4,
+		ringcache.WithEvictBatch[int, int](3),
+		ringcache.WithBatchEvictCallback[int, int](func(es []ringcache.Entry[int, int]) {
+			var keys []int
+			for _, e := range es {
+				keys = append(keys, e.Key)
+			}
+			batches = append(batches, keys)
+		}))
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, i)
+	}
+
+	k, _, ev := rc.PushEvicting(5, 5)
+	if !ev || k != 1 {
+		t.Fatalf("PushEvicting should report the oldest victim 1, got %d, %v", k, ev)
+	}
+	if fmt.Sprint(batches) != "[[1 2 3]]" {
+		t.Fatalf("batches = %v, want [[1 2 3]]", batches)
+	}
+	if rc.Size() != 2 {
+		t.Fatalf("size = %d, want 2", rc.Size())
+	}
+	// The two freed slots absorb new keys without evicting.
+	if rc.Push(6, 6) || rc.Push(7, 7) {
+		t.Fatalf("pushes into freed slots must not evict")
+	}
+	if !rc.Push(8, 8) || fmt.Sprint(batches[1]) != "[4 5 6]" {
+		t.Fatalf("second batch = %v, want [4 5 6]", batches)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("cache unhealthy: %v", err)
+	}
+}
+
+func TestWithEvictBatch_SkipsPinned(t *testing.T) {
+	rc, _ := ringcache.New[int, int](3, ringcache.WithEvictBatch[int, int](2))
+	rc.Push(1, 1)
+	rc.Push(2, 2)
+	rc.Push(3, 3)
+	rc.Pin(2)
+	rc.Push(4, 4) // evicts 1 and 3, keeps the pinned 2
+	if rc.Has(1) || rc.Has(3) || !rc.Has(2) || !rc.Has(4) {
+		t.Fatalf("unexpected contents after batch eviction: %v", rc.SortedKeys(func(a, b int) bool { return a < b }))
+	}
+	if _, err := ringcache.New[int, int](1, ringcache.WithEvictBatch[int, int](-1)); err == nil {
+		t.Fatalf("expected error for a negative batch size")
+	}
+}
+
+func TestOnFullOnNotFull_FireOnEdges(t *testing.T) {
+	var events []string
+	rc, _ := ringcache.New[int, int](2,
+		ringcache.WithOnFull[int, int](func() { events = append(events, "full") }),
+		ringcache.WithOnNotFull[int, int](func() { events = append(events, "notfull") }))
+
+	rc.Push(1, 1)
+	rc.Push(2, 2) // becomes full
+	rc.Push(3, 3) // stays full: no event
+	rc.Push(4, 4)
+	rc.Delete(3) // drops below full
+	rc.Delete(4) // still not full: no event
+	rc.Push(5, 5)
+	rc.Push(6, 6) // full again
+	rc.Clear()    // not full again
+
+	if got := fmt.Sprint(events); got != "[full notfull full notfull]" {
+		t.Fatalf("events = %s", got)
+	}
+}
+
+func TestOnFull_Swap(t *testing.T) {
+	var fullA, fullB int
+	a, _ := ringcache.New[int, int](1, ringcache.WithOnFull[int, int](func() { fullA++ }))
+	b, _ := ringcache.New[int, int](1, ringcache.WithOnFull[int, int](func() { fullB++ }))
+	b.Push(1, 1)
+	if err := a.Swap(b); err != nil {
+		t.Fatalf("Swap: %v", err)
+	}
+	if fullA != 1 || fullB != 1 {
+		t.Fatalf("full hooks fired a=%d b=%d, want 1 and 1", fullA, fullB)
+	}
+}
+
+func TestPushAllOrdered(t *testing.T) {
+	var calls []int
+	rc, _ := ringcache.NewWithEvictCallback[int, string](3, func(k int, _ string) { calls = append(calls, k) })
+	rc.Push(100, "old")
+
+	in := []ringcache.Entry[int, string]{
+		{Key: 1, Value: "a"}, {Key: 2, Value: "b"}, {Key: 3, Value: "c"},
+		{Key: 1, Value: "a2"}, {Key: 4, Value: "d"},
+	}
+	survivors, evicted := rc.PushAllOrdered(in)
+	if !slices.Equal(survivors, []int{3, 1, 4}) {
+		t.Fatalf("survivors = %v, want [3 1 4]", survivors)
+	}
+	var evictedKeys []int
+	for _, e := range evicted {
+		evictedKeys = append(evictedKeys, e.Key)
+	}
+	if !slices.Equal(evictedKeys, []int{100, 2}) || evicted[0].Value != "old" {
+		t.Fatalf("evicted = %v, want 100 then 2", evicted)
+	}
+	if !slices.Equal(calls, evictedKeys) {
+		t.Fatalf("callbacks = %v, want %v", calls, evictedKeys)
+	}
+	if v, _ := rc.Load(1); v != "a2" {
+		t.Fatalf("Load(1) = %q, want the last pushed value", v)
+	}
+}
+
+func TestPushAllOrdered_Rejected(t *testing.T) {
+	rc, _ := ringcache.New[int, int](2, ringcache.WithAdmit[int, int](func(_, v int) bool { return v > 0 }))
+	rc.Push(1, 1)
+	survivors, evicted := rc.PushAllOrdered([]ringcache.Entry[int, int]{{Key: 1, Value: -1}, {Key: 2, Value: 2}})
+	if !slices.Equal(survivors, []int{2}) || len(evicted) != 0 {
+		t.Fatalf("survivors = %v, evicted = %v; want [2], none", survivors, evicted)
+	}
+}
+
+func TestPushAllOrdered_BatchCallbackGetsOwnSlice(t *testing.T) {
+	var kept []ringcache.Entry[int, int]
+	rc, _ := ringcache.New[int, int](1, ringcache.WithBatchEvictCallback[int, int](func(batch []ringcache.Entry[int, int]) {
+		kept = batch
+	}))
+	rc.Push(1, 1)
+	_, evicted := rc.PushAllOrdered([]ringcache.Entry[int, int]{{Key: 2, Value: 2}})
+	evicted[0].Key = 99
+	if len(kept) != 1 || kept[0].Key != 1 {
+		t.Fatalf("callback slice = %v, want [{1 1}] unaffected by changes to the result", kept)
+	}
+}
+
+func TestTryPush(t *testing.T) {
+	rc, _ := ringcache.New[int, string](1)
+	if ev, err := rc.TryPush(1, "one"); ev || err != nil {
+		t.Fatalf("TryPush(1) = %v, %v; want false, nil", ev, err)
+	}
+	if ev, err := rc.TryPush(2, "two"); !ev || err != nil {
+		t.Fatalf("TryPush(2) = %v, %v; want true, nil", ev, err)
+	}
+
+	rc.Pin(2)
+	if _, err := rc.TryPush(3, "three"); !errors.Is(err, ringcache.ErrAllPinned) {
+		t.Fatalf("err = %v, want ErrAllPinned", err)
+	}
+	rc.Unpin(2)
+
+	rc.Freeze()
+	if _, err := rc.TryPush(3, "three"); !errors.Is(err, ringcache.ErrFrozen) {
+		t.Fatalf("err = %v, want ErrFrozen", err)
+	}
+	if rc.Has(3) {
+		t.Fatalf("failed TryPush must not store")
+	}
+}
+
+func TestLoadOrDefault(t *testing.T) {
+	rc, _ := ringcache.New[string, int](2)
+	rc.Push("a", 1)
+	if got := rc.LoadOrDefault("a", 7); got != 1 {
+		t.Fatalf("LoadOrDefault(a) = %d, want 1", got)
+	}
+	if got := rc.LoadOrDefault("b", 7); got != 7 {
+		t.Fatalf("LoadOrDefault(b) = %d, want 7", got)
+	}
+	if rc.Has("b") {
+		t.Fatalf("LoadOrDefault must not store the default")
+	}
+}
+
+func TestUpcomingEvictions(t *testing.T) {
+	rc, _ := ringcache.New[int, string](4)
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, "v")
+	}
+	rc.Delete(2)
+	rc.Pin(3)
+
+	// Slot order from next: 1, free, 3 (pinned, skipped), 4; then the walk wraps into
+	// slots the predicted pushes filled themselves.
+	got := rc.UpcomingEvictions(10)
+	if fmt.Sprint(got) != "[1 4]" {
+		t.Fatalf("UpcomingEvictions(10) = %v, want [1 4]", got)
+	}
+	if got := rc.UpcomingEvictions(1); fmt.Sprint(got) != "[1]" {
+		t.Fatalf("UpcomingEvictions(1) = %v, want [1]", got)
+	}
+
+	// The prediction matches what actually happens.
+	var evicted []int
+	for k := 10; k < 13; k++ {
+		if ek, _, ok := rc.PushEvicting(k, "new"); ok {
+			evicted = append(evicted, ek)
+		}
+	}
+	if fmt.Sprint(evicted) != "[1 4]" {
+		t.Fatalf("actual evictions = %v, want [1 4]", evicted)
+	}
+	if got := rc.UpcomingEvictions(0); got != nil {
+		t.Fatalf("UpcomingEvictions(0) = %v, want nil", got)
+	}
+}
+
+func TestWithAdmit(t *testing.T) {
+	var evicted int
+	rc, _ := ringcache.NewWithEvictCallback[string, string](1, func(string, string) { evicted++ },
+		ringcache.WithAdmit[string, string](func(_ string, v string) bool { return len(v) <= 3 }))
+	rc.Push("a", "ok")
+	if rc.Push("b", "too long") {
+		t.Fatalf("rejected Push must not evict")
+	}
+	if rc.Has("b") || !rc.Has("a") || evicted != 0 {
+		t.Fatalf("rejected entry stored or victim evicted (evicted=%d)", evicted)
+	}
+	if _, err := rc.TryPush("c", "nope!"); !errors.Is(err, ringcache.ErrRejected) {
+		t.Fatalf("TryPush err = %v, want ErrRejected", err)
+	}
+	if _, _, ev := rc.PushEvicting("a", "updated"); ev {
+		t.Fatalf("rejected update must not evict")
+	}
+	if v, _ := rc.Load("a"); v != "ok" {
+		t.Fatalf("rejected update changed the value to %q", v)
+	}
+	if !rc.Touched("a") {
+		t.Fatalf("promotion is not subject to admission")
+	}
+}
+
+func TestPopNewest(t *testing.T) {
+	var evicted []int
+	rc, _ := ringcache.NewWithEvictCallback[int, string](3, func(k int, _ string) {
+		evicted = append(evicted, k)
+	})
+	if _, _, ok := rc.PopNewest(); ok {
+		t.Fatalf("PopNewest on an empty cache should report false")
+	}
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, fmt.Sprint(i)) // 4 overwrites slot 0
+	}
+	evicted = nil
+
+	k, v, ok := rc.PopNewest()
+	if !ok || k != 4 || v != "4" {
+		t.Fatalf("PopNewest() = %d, %q, %v; want 4, 4, true", k, v, ok)
+	}
+	if rc.NextIndex() != 0 {
+		t.Fatalf("next = %d, want 0 (rewound to the popped slot)", rc.NextIndex())
+	}
+	// Wrap-around backwards: the next newest is 3 in the last slot.
+	if k, _, _ := rc.PopNewest(); k != 3 {
+		t.Fatalf("second PopNewest = %d, want 3", k)
+	}
+	if fmt.Sprint(evicted) != "[4 3]" {
+		t.Fatalf("callback saw %v, want [4 3]", evicted)
+	}
+	// The rewound slot is reused without evicting the remaining entry.
+	if rc.Push(5, "5") || !rc.Has(2) {
+		t.Fatalf("Push after PopNewest should reuse the freed slot")
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("cache unhealthy after PopNewest: %v", err)
+	}
+}
+
+func TestRangeBatched(t *testing.T) {
+	rc, _ := ringcache.New[int, int](10)
+	for i := 0; i < 7; i++ {
+		rc.Push(i, i*i)
+	}
+
+	var sizes []int
+	var keys []int
+	rc.RangeBatched(3, func(b []ringcache.Entry[int, int]) bool {
+		sizes = append(sizes, len(b))
+		for _, e := range b {
+			keys = append(keys, e.Key)
+			if e.Value != e.Key*e.Key {
+				t.Fatalf("entry %d has value %d", e.Key, e.Value)
+			}
+		}
+		rc.Push(100+len(keys), 0) // writes between batches must not deadlock
+		return true
+	})
+	if fmt.Sprint(sizes[:3]) != "[3 3 1]" || fmt.Sprint(keys[:7]) != "[0 1 2 3 4 5 6]" {
+		t.Fatalf("batches %v, keys %v", sizes, keys)
+	}
+
+	var calls int
+	rc.RangeBatched(0, func([]ringcache.Entry[int, int]) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("f called %d times after returning false, want 1", calls)
+	}
+}
+
+func TestCounters_MonotonicOverManyOps(t *testing.T) {
+	rc, _ := ringcache.New[int, int](64, ringcache.WithSequence[int, int]())
+	var lastGen, lastSeq uint64
+	for i := 0; i < 1_000_000; i++ {
+		k := i % 100
+		rc.Push(k, i)
+		gen := rc.Generation()
+		seq, ok := rc.Sequence(k)
+		if !ok || seq <= lastSeq || gen <= lastGen {
+			t.Fatalf("op %d: seq %d after %d, gen %d after %d", i, seq, lastSeq, gen, lastGen)
+		}
+		lastGen, lastSeq = gen, seq
+	}
+}
+
+func TestRotateSnapshot(t *testing.T) {
+	var evicted int
+	rc, _ := ringcache.NewWithEvictCallback[string, int](4, func(string, int) { evicted++ })
+	rc.Push("a", 1)
+	rc.Push("b", 2)
+
+	snap := rc.RotateSnapshot()
+	if len(snap) != 2 || snap["a"] != 1 || snap["b"] != 2 {
+		t.Fatalf("snapshot = %v", snap)
+	}
+	if rc.Size() != 0 || evicted != 0 {
+		t.Fatalf("size %d, callbacks %d; want 0, 0", rc.Size(), evicted)
+	}
+	rc.Push("c", 3)
+	if snap := rc.RotateSnapshot(); len(snap) != 1 || snap["c"] != 3 {
+		t.Fatalf("second window = %v", snap)
+	}
+}
+
+func TestRotateSnapshot_NoLostUpdates(t *testing.T) {
+	rc, _ := ringcache.New[int, int](1 << 16)
+	const n = 20000
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < n; i++ {
+			rc.Push(i, i)
+		}
+	}()
+	seen := make(map[int]bool)
+	collect := func() {
+		for k := range rc.RotateSnapshot() {
+			if seen[k] {
+				t.Fatalf("key %d returned twice", k)
+			}
+			seen[k] = true
+		}
+	}
+	for {
+		select {
+		case <-done:
+			collect()
+			if len(seen) != n {
+				t.Fatalf("collected %d keys, want %d", len(seen), n)
+			}
+			return
+		default:
+			collect()
+		}
+	}
+}
+
+func TestWithOrderedClear(t *testing.T) {
+	var order []int
+	rc, _ := ringcache.NewWithEvictCallback[int, int](8, func(k, _ int) { order = append(order, k) },
+		ringcache.WithOrderedClear[int, int]())
+	for i := 0; i < 12; i++ {
+		rc.Push(i, i) // wraps: 4..11 remain, oldest first
+	}
+	order = nil
+	rc.Clear()
+	if fmt.Sprint(order) != "[4 5 6 7 8 9 10 11]" {
+		t.Fatalf("Clear order = %v", order)
+	}
+}
+
+func TestWithSkipNoopUpdates(t *testing.T) {
+	rc, _ := ringcache.New[string, int](2, ringcache.WithSkipNoopUpdates[string, int]())
+	rc.Push("a", 1)
+	rc.Push("b", 2)
+	gen := rc.Generation()
+
+	if changed, _ := rc.PushChanged("a", 1); changed {
+		t.Fatalf("pushing an equal value should report no change")
+	}
+	if rc.Generation() != gen {
+		t.Fatalf("no-op push bumped the generation")
+	}
+	if v, _ := rc.Version("a"); v != 1 {
+		t.Fatalf("no-op push bumped the version to %d", v)
+	}
+	// "a" was not promoted, so it is still the oldest entry.
+	rc.Push("c", 3)
+	if rc.Has("a") {
+		t.Fatalf("a should have been evicted: the no-op push must not move it")
+	}
+
+	if changed, _ := rc.PushChanged("b", 20); !changed {
+		t.Fatalf("pushing a different value should report a change")
+	}
+	if changed, _ := rc.PushChanged("d", 4); !changed {
+		t.Fatalf("pushing a new key should report a change")
+	}
+
+	// Without the option every push writes.
+	plain, _ := ringcache.New[string, int](1)
+	plain.Push("a", 1)
+	if changed, _ := plain.PushChanged("a", 1); !changed {
+		t.Fatalf("without WithSkipNoopUpdates every push is a write")
+	}
+}
+
+func TestWithScoreEvict(t *testing.T) {
+	var evicted []string
+	rc, _ := ringcache.NewWithEvictCallback[string, int](3, func(k string, _ int) {
+		evicted = append(evicted, k)
+	}, ringcache.WithScoreEvict[string, int](func(_ string, v int) int64 { return int64(v) }))
+	rc.Push("a", 5)
+	rc.Push("b", 1)
+	rc.Push("c", 3)
+
+	if k, _, ok := rc.WouldEvict("d"); !ok || k != "b" {
+		t.Fatalf("WouldEvict = %q, %v; want b", k, ok)
+	}
+	rc.Push("d", 4) // evicts b (score 1)
+	rc.Pin("c")
+	rc.Push("e", 9) // c (3) is pinned, so d (4) goes
+	rc.Push("f", 5) // c is still pinned; a (5) scores below e (9)
+	if fmt.Sprint(evicted) != "[b d a]" {
+		t.Fatalf("evicted %v, want [b d a]", evicted)
+	}
+	if rc.UpcomingEvictions(2) != nil {
+		t.Fatalf("UpcomingEvictions cannot predict score eviction")
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("cache unhealthy: %v", err)
+	}
+}
+
+func TestWouldEvict(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	if _, _, ev := rc.WouldEvict(1); ev {
+		t.Fatalf("empty cache should not evict")
+	}
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+
+	k, v, ev := rc.WouldEvict(3)
+	if !ev || k != 1 || v != "one" {
+		t.Fatalf("WouldEvict(3) = (%d,%q,%v), want (1,\"one\",true)", k, v, ev)
+	}
+	if !rc.Has(1) || rc.Has(3) {
+		t.Fatalf("WouldEvict must not mutate the cache")
+	}
+	if _, _, ev := rc.WouldEvict(2); ev {
+		t.Fatalf("present key should report no eviction")
+	}
+
+	rc.Pin(1)
+	if k, _, ev := rc.WouldEvict(3); !ev || k != 2 {
+		t.Fatalf("pinned victim should be skipped, got (%d,%v)", k, ev)
+	}
+	rc.Pin(2)
+	if _, _, ev := rc.WouldEvict(3); ev {
+		t.Fatalf("fully pinned cache should report no eviction")
+	}
+}
+
+func TestNilCallbackOptions_NoPanic(t *testing.T) {
+	rc, err := ringcache.New[int, string](1,
+		ringcache.WithEvictCallback[int, string](nil),
+		ringcache.WithBatchEvictCallback[int, string](nil),
+		ringcache.WithPanicHandler[int, string](nil),
+		ringcache.WithWriteBack[int, string](1, nil),
+		ringcache.WithWriteBackErrorHandler[int, string](nil),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rc.Push(1, "one")
+	rc.Push(2, "two") // evicts through every (nil) hook
+	rc.Delete(2)
+	rc.Push(3, "three")
+	rc.Clear()
+	if err := rc.Flush(); err != nil {
+		t.Fatalf("Flush with nil flush function: %v", err)
+	}
+}
+
+func TestWithEvictCallback_OverridesConstructorArgument(t *testing.T) {
+	var fromArg, fromOpt int
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, func(int, string) { fromArg++ },
+		ringcache.WithEvictCallback[int, string](func(int, string) { fromOpt++ }))
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	if fromArg != 0 || fromOpt != 1 {
+		t.Fatalf("calls arg=%d opt=%d, want 0 and 1", fromArg, fromOpt)
+	}
+
+	disabled, _ := ringcache.NewWithEvictCallback[int, string](1, func(int, string) { fromArg++ },
+		ringcache.WithEvictCallback[int, string](nil))
+	disabled.Push(1, "one")
+	disabled.Push(2, "two")
+	if fromArg != 0 {
+		t.Fatalf("WithEvictCallback(nil) should disable the callback")
+	}
+}
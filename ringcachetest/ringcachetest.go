@@ -0,0 +1,67 @@
+// Package ringcachetest provides assertion helpers for tests of code built on ringcache.
+//
+// Every helper takes a testing.TB, marks itself as a helper, reports failures with t.Errorf
+// (so a test can collect several) and returns whether the assertion held. Helpers that only
+// check read through RingCache.Peek, so they leave statistics, hooks and expired entries as
+// they were.
+package ringcachetest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+// AssertContains checks that c holds key with value want.
+func AssertContains[K, V comparable](t testing.TB, c *ringcache.RingCache[K, V], key K, want V) bool {
+	t.Helper()
+	got, ok := c.Peek(key)
+	switch {
+	case !ok:
+		t.Errorf("key %v not in cache, want value %v", key, want)
+		return false
+	case got != want:
+		t.Errorf("key %v = %v, want %v", key, got, want)
+		return false
+	}
+	return true
+}
+
+// AssertMissing checks that c does not hold key.
+func AssertMissing[K comparable, V any](t testing.TB, c *ringcache.RingCache[K, V], key K) bool {
+	t.Helper()
+	if _, ok := c.Peek(key); ok {
+		t.Errorf("key %v is in cache, want it absent", key)
+		return false
+	}
+	return true
+}
+
+// AssertSize checks that c holds exactly n entries.
+func AssertSize[K comparable, V any](t testing.TB, c *ringcache.RingCache[K, V], n int) bool {
+	t.Helper()
+	if got := c.Size(); got != n {
+		t.Errorf("cache size = %d, want %d", got, n)
+		return false
+	}
+	return true
+}
+
+// AssertEvicted pushes the entries into rc in order and checks that the pushes evicted exactly
+// the keys want, in that order. Evictions are observed through PushEvicting, so the cache's own
+// callbacks still run. With WithEvictBatch only the first key of each batch is observed.
+func AssertEvicted[K comparable, V any](t testing.TB, rc *ringcache.RingCache[K, V], pushes []ringcache.Entry[K, V], want ...K) bool {
+	t.Helper()
+	var got []K
+	for _, e := range pushes {
+		if k, _, ok := rc.PushEvicting(e.Key, e.Value); ok {
+			got = append(got, k)
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("pushes evicted %v, want %v", got, want)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package ringcachetest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chi07/ringcache"
+	"github.com/chi07/ringcache/ringcachetest"
+)
+
+// recorder captures failures instead of failing the enclosing test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions_Pass(t *testing.T) {
+	rc, _ := ringcache.New[string, int](2)
+	rc.Push("a", 1)
+
+	ringcachetest.AssertContains(t, rc, "a", 1)
+	ringcachetest.AssertMissing(t, rc, "z")
+	ringcachetest.AssertSize(t, rc, 1)
+	ringcachetest.AssertEvicted(t, rc, []ringcache.Entry[string, int]{
+		{Key: "b", Value: 2}, {Key: "c", Value: 3}, {Key: "d", Value: 4},
+	}, "a", "b")
+}
+
+func TestAssertions_Fail(t *testing.T) {
+	rc, _ := ringcache.New[string, int](1)
+	rc.Push("a", 1)
+	r := &recorder{TB: t}
+
+	if ringcachetest.AssertContains(r, rc, "a", 2) {
+		t.Fatalf("wrong value should fail")
+	}
+	if ringcachetest.AssertContains(r, rc, "z", 1) {
+		t.Fatalf("missing key should fail")
+	}
+	if ringcachetest.AssertMissing(r, rc, "a") {
+		t.Fatalf("present key should fail AssertMissing")
+	}
+	if ringcachetest.AssertSize(r, rc, 3) {
+		t.Fatalf("wrong size should fail")
+	}
+	if ringcachetest.AssertEvicted(r, rc, []ringcache.Entry[string, int]{{Key: "b", Value: 2}}, "z") {
+		t.Fatalf("wrong eviction should fail")
+	}
+	if len(r.errors) != 5 {
+		t.Fatalf("recorded %d failures, want 5: %q", len(r.errors), r.errors)
+	}
+	if r.errors[0] != "key a = 1, want 2" {
+		t.Fatalf("first failure = %q", r.errors[0])
+	}
+}
+
+func TestAssertions_LeaveStateAlone(t *testing.T) {
+	var hooks int
+	rc, _ := ringcache.New[string, int](2,
+		ringcache.WithOnHit[string, int](func(string, int) { hooks++ }),
+		ringcache.WithOnMiss[string, int](func(string) { hooks++ }))
+	rc.Push("a", 1)
+
+	ringcachetest.AssertContains(t, rc, "a", 1)
+	ringcachetest.AssertMissing(t, rc, "z")
+	if s := rc.Stats(); s.Hits != 0 || s.Misses != 0 || hooks != 0 {
+		t.Fatalf("assertions changed the cache: hits %d, misses %d, hooks %d", s.Hits, s.Misses, hooks)
+	}
+}
@@ -0,0 +1,66 @@
+package ringcache
+
+// RingSet is a bounded set of the most recently added keys, e.g. a dedup window of recently
+// processed message IDs. It is a thin wrapper over RingCache with zero-size values, so it has
+// the same ring, eviction and concurrency semantics and no per-key value storage.
+type RingSet[K comparable] struct {
+	rc *RingCache[K, struct{}]
+}
+
+// NewRingSet creates a RingSet holding up to capacity (> 0) keys.
+// onEvict, if not nil, is called outside the lock for every key evicted by the ring or removed.
+func NewRingSet[K comparable](capacity int, onEvict func(key K)) (*RingSet[K], error) {
+	var cb EvictCallback[K, struct{}]
+	if onEvict != nil {
+		cb = func(k K, _ struct{}) { onEvict(k) }
+	}
+	rc, err := NewWithEvictCallback(capacity, cb)
+	if err != nil {
+		return nil, err
+	}
+	return &RingSet[K]{rc: rc}, nil
+}
+
+// Add inserts key as the newest member, moving it to the head if already present.
+// Returns true if an older key was evicted to make room.
+func (s *RingSet[K]) Add(key K) (evicted bool) {
+	return s.rc.Push(key, struct{}{})
+}
+
+// AddAndCount adds key like Add and returns how many times it has been added during its current
+// stay in the set: 1 for a key that was absent, then 2, 3, ... for repeats, which flags
+// duplicates or replays of e.g. idempotency keys. The count starts over once the key has been
+// evicted or removed. It is the entry's version (see RingCache.Version), so it costs no extra
+// memory.
+func (s *RingSet[K]) AddAndCount(key K) int {
+	c := s.rc
+	c.lockTimed()
+	victims, _, _ := c.store(nil, key, struct{}{})
+	n := c.versions[key]
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(victims...)
+	runHook(edge)
+	return int(n)
+}
+
+// Contains reports whether key is in the set.
+func (s *RingSet[K]) Contains(key K) bool {
+	return s.rc.Has(key)
+}
+
+// Remove deletes key and returns true if it was present.
+func (s *RingSet[K]) Remove(key K) bool {
+	return s.rc.Delete(key)
+}
+
+// Len returns the number of keys in the set.
+func (s *RingSet[K]) Len() int {
+	return s.rc.Size()
+}
+
+// Capacity returns the maximum number of keys the set holds.
+func (s *RingSet[K]) Capacity() int {
+	return s.rc.Capacity()
+}
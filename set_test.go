@@ -0,0 +1,64 @@
+package ringcache_test
+
+import (
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestRingSet(t *testing.T) {
+	var evicted []string
+	s, err := ringcache.NewRingSet[string](2, func(k string) { evicted = append(evicted, k) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Capacity() != 2 {
+		t.Fatalf("capacity = %d, want 2", s.Capacity())
+	}
+
+	s.Add("a")
+	s.Add("b")
+	if !s.Contains("a") || !s.Contains("b") || s.Len() != 2 {
+		t.Fatalf("expected a and b in the set")
+	}
+	if !s.Add("c") {
+		t.Fatalf("expected eviction when adding to a full set")
+	}
+	if s.Contains("a") || len(evicted) != 1 || evicted[0] != "a" {
+		t.Fatalf("expected a to be evicted, evicted=%v", evicted)
+	}
+
+	if !s.Remove("b") || s.Remove("b") {
+		t.Fatalf("Remove should succeed exactly once")
+	}
+	if s.Len() != 1 {
+		t.Fatalf("len = %d, want 1", s.Len())
+	}
+}
+
+func TestRingSet_InvalidCapacity(t *testing.T) {
+	if _, err := ringcache.NewRingSet[int](0, nil); err == nil {
+		t.Fatalf("expected error for capacity=0")
+	}
+}
+
+func TestRingSet_AddAndCount(t *testing.T) {
+	s, _ := ringcache.NewRingSet[string](2, nil)
+	for i, want := range []int{1, 2, 3} {
+		if got := s.AddAndCount("a"); got != want {
+			t.Fatalf("add #%d: count = %d, want %d", i+1, got, want)
+		}
+	}
+	s.AddAndCount("b")
+	s.AddAndCount("c") // evicts a, the oldest member
+	if s.Contains("a") {
+		t.Fatalf("a should have been evicted")
+	}
+	if got := s.AddAndCount("a"); got != 1 {
+		t.Fatalf("count after eviction = %d, want 1", got)
+	}
+	s.Remove("c")
+	if got := s.AddAndCount("c"); got != 1 {
+		t.Fatalf("count after Remove = %d, want 1", got)
+	}
+}
@@ -0,0 +1,83 @@
+package ringcache
+
+import "maps"
+
+// State is a deep copy of the cache's internal ring layout, returned by State. Two States
+// can be compared with reflect.DeepEqual, which lets white-box tests assert the exact layout
+// an operation produced. The layout is an implementation detail: it is stable within a
+// release but may change between releases.
+type State[K comparable, V any] struct {
+	// Keys holds the key stored in each slot; free slots hold the zero K.
+	Keys []K
+	// Occupied reports, per slot, whether it holds an entry.
+	Occupied []bool
+	// Pos maps every cached key to its slot.
+	Pos map[K]int
+	// Items maps every cached key to its value.
+	Items map[K]V
+	// Next is the slot the next Push of a new key starts from (see NextIndex).
+	Next int
+}
+
+// State returns a copy of the internal ring state, taken under the read lock. It is meant for
+// tests and debugging; nothing returned aliases the cache, so it may be kept and modified.
+func (c *RingCache[K, V]) State() State[K, V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return State[K, V]{
+		Keys:     append([]K{}, c.keys...),
+		Occupied: append([]bool{}, c.occupied...),
+		Pos:      maps.Clone(c.pos),
+		Items:    maps.Clone(c.items),
+		Next:     c.next,
+	}
+}
+
+// OccupancyBitmap returns the occupied slots as a packed bitmap taken under the read lock:
+// slot i is bit i%64 of word i/64, least significant bit first, and a set bit means the slot
+// holds an entry. Bits past Capacity() in the last word are always zero. Counting set bits
+// (math/bits.OnesCount64) or gaps between them is much cheaper than walking the ring.
+func (c *RingCache[K, V]) OccupancyBitmap() []uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	bm := make([]uint64, (c.capacity+63)/64)
+	for i, occ := range c.occupied {
+		if occ {
+			bm[i/64] |= 1 << (i % 64)
+		}
+	}
+	return bm
+}
+
+// RingOrder returns the keys in physical slot order, slot 0 first (not eviction order), together
+// with the slot the next Push starts from, read under one read lock so the pair is consistent.
+// The slice has Capacity() elements; free slots hold the zero K. If the zero K can be a real
+// key, tell the two apart with OccupancyBitmap or State. Replaying the keys into the same slots
+// (see MoveToIndex) and the same next position reproduces the ring layout exactly.
+func (c *RingCache[K, V]) RingOrder() (keys []K, next int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]K{}, c.keys...), c.next
+}
+
+// FragmentedSlots returns how many free slots lie between live entries: walking the ring oldest
+// first from NextIndex, the free slots after the oldest and before the newest entry. Free slots
+// ahead of the next write position are ordinary spare capacity and are not counted, so a ring
+// filled only by Push reports 0. Gaps come from Delete and similar removals; Compact closes them.
+// It reads the occupancy under the read lock in O(Capacity()).
+func (c *RingCache[K, V]) FragmentedSlots() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	gaps, pending, seen := 0, 0, false
+	for i := 0; i < c.capacity; i++ {
+		if c.occupied[(c.next+i)%c.capacity] {
+			if seen {
+				gaps += pending
+			}
+			seen, pending = true, 0
+		} else {
+			pending++
+		}
+	}
+	return gaps
+}
@@ -0,0 +1,180 @@
+package ringcache_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestState_Layout(t *testing.T) {
+	rc, _ := ringcache.New[string, int](3)
+	rc.Push("a", 1)
+	rc.Push("b", 2)
+	rc.Delete("a")
+
+	want := ringcache.State[string, int]{
+		Keys:     []string{"", "b", ""},
+		Occupied: []bool{false, true, false},
+		Pos:      map[string]int{"b": 1},
+		Items:    map[string]int{"b": 2},
+		Next:     2,
+	}
+	if got := rc.State(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("State() = %+v, want %+v", got, want)
+	}
+}
+
+func TestState_IsACopy(t *testing.T) {
+	rc, _ := ringcache.New[string, int](2)
+	rc.Push("a", 1)
+	s := rc.State()
+	s.Keys[0] = "x"
+	s.Items["a"] = 99
+	if v, _ := rc.Load("a"); v != 1 {
+		t.Fatalf("modifying the State changed the cache: a = %d", v)
+	}
+	if got := rc.State(); got.Keys[0] != "a" {
+		t.Fatalf("modifying the State changed the ring: %v", got.Keys)
+	}
+}
+
+func TestOccupancyBitmap(t *testing.T) {
+	rc, _ := ringcache.New[int, int](70)
+	for i := 0; i < 70; i++ {
+		rc.Push(i, i)
+	}
+	rc.Delete(1)
+	rc.Delete(64)
+
+	bm := rc.OccupancyBitmap()
+	if len(bm) != 2 {
+		t.Fatalf("bitmap has %d words, want 2", len(bm))
+	}
+	if bm[0] != ^uint64(0)&^(1<<1) {
+		t.Fatalf("word 0 = %#x", bm[0])
+	}
+	if bm[1] != 0b111110 {
+		t.Fatalf("word 1 = %#b, want 0b111110", bm[1])
+	}
+}
+
+func TestCompact(t *testing.T) {
+	var evicted []int
+	rc, _ := ringcache.NewWithEvictCallback[int, int](4, func(k, _ int) { evicted = append(evicted, k) })
+	for i := 1; i <= 4; i++ {
+		rc.Push(i, i*10)
+	}
+	rc.Delete(2)
+	rc.Delete(3)
+	evicted = nil
+
+	rc.Compact()
+	want := ringcache.State[int, int]{
+		Keys:     []int{1, 4, 0, 0},
+		Occupied: []bool{true, true, false, false},
+		Pos:      map[int]int{1: 0, 4: 1},
+		Items:    map[int]int{1: 10, 4: 40},
+		Next:     2,
+	}
+	if got := rc.State(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("State() after Compact = %+v, want %+v", got, want)
+	}
+	if len(evicted) != 0 {
+		t.Fatalf("Compact must not evict, callback saw %v", evicted)
+	}
+
+	// The freed slots are used before anything is evicted, and 1 stays the oldest entry.
+	rc.Push(5, 50)
+	rc.Push(6, 60)
+	rc.Push(7, 70)
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("evicted %v, want [1]", evicted)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("cache unhealthy after Compact: %v", err)
+	}
+}
+
+func TestRingOrder(t *testing.T) {
+	rc, _ := ringcache.New[string, int](3)
+	rc.Push("a", 1)
+	rc.Push("b", 2)
+	rc.Push("c", 3)
+	rc.Push("d", 4) // overwrites slot 0
+	rc.Delete("b")
+
+	keys, next := rc.RingOrder()
+	if !reflect.DeepEqual(keys, []string{"d", "", "c"}) || next != 1 {
+		t.Fatalf("RingOrder() = %q, %d; want [d  c], 1", keys, next)
+	}
+}
+
+func TestMoveToIndex(t *testing.T) {
+	rc, _ := ringcache.New[string, int](3)
+	rc.Push("a", 1)
+	rc.Push("b", 2)
+
+	if err := rc.MoveToIndex("a", 2); err != nil {
+		t.Fatalf("MoveToIndex: %v", err)
+	}
+	keys, next := rc.RingOrder()
+	if !reflect.DeepEqual(keys, []string{"", "b", "a"}) || next != 2 {
+		t.Fatalf("RingOrder() = %q, %d; want [ b a], 2", keys, next)
+	}
+	if v, _ := rc.Load("a"); v != 1 {
+		t.Fatalf("moved key lost its value: %d", v)
+	}
+
+	for _, tc := range []struct {
+		key  string
+		idx  int
+		want error
+	}{
+		{"missing", 0, ringcache.ErrKeyNotFound},
+		{"a", 3, ringcache.ErrSlotOutOfRange},
+		{"a", -1, ringcache.ErrSlotOutOfRange},
+		{"a", 1, ringcache.ErrSlotOccupied},
+		{"a", 2, nil},
+	} {
+		if err := rc.MoveToIndex(tc.key, tc.idx); !errors.Is(err, tc.want) {
+			t.Fatalf("MoveToIndex(%q, %d) = %v, want %v", tc.key, tc.idx, err, tc.want)
+		}
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatalf("cache unhealthy after MoveToIndex: %v", err)
+	}
+
+	rc.Freeze()
+	if err := rc.MoveToIndex("a", 0); !errors.Is(err, ringcache.ErrFrozen) {
+		t.Fatalf("MoveToIndex on a frozen cache = %v, want ErrFrozen", err)
+	}
+	if p, _ := rc.PeekPos("a"); p != 2 {
+		t.Fatalf("frozen MoveToIndex moved the key to slot %d", p)
+	}
+}
+
+func TestFragmentedSlots(t *testing.T) {
+	rc, _ := ringcache.New[int, int](6)
+	for i := 0; i < 4; i++ {
+		rc.Push(i, i)
+	}
+	if n := rc.FragmentedSlots(); n != 0 {
+		t.Fatalf("pushes only: FragmentedSlots = %d, want 0", n)
+	}
+	rc.Delete(1)
+	rc.Delete(2)
+	if n := rc.FragmentedSlots(); n != 2 {
+		t.Fatalf("after two interior deletes: FragmentedSlots = %d, want 2", n)
+	}
+	rc.Delete(3) // the newest entry: the gap is now at the tail
+	if n := rc.FragmentedSlots(); n != 0 {
+		t.Fatalf("after deleting the newest: FragmentedSlots = %d, want 0", n)
+	}
+	rc.Push(4, 4)
+	rc.Compact()
+	if n := rc.FragmentedSlots(); n != 0 {
+		t.Fatalf("after Compact: FragmentedSlots = %d, want 0", n)
+	}
+}
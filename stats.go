@@ -0,0 +1,75 @@
+package ringcache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a point-in-time copy of the cache's operational counters.
+// Hits and Misses count Load lookups only, so they describe how well the cache serves values.
+// Membership tests through Has are counted separately in HasHits and HasMisses and never
+// affect the hit ratio Hits/(Hits+Misses).
+type Stats struct {
+	// Hits and Misses count Load calls that found, respectively did not find, their key.
+	Hits   uint64
+	Misses uint64
+	// HasHits and HasMisses count Has calls that returned true, respectively false.
+	HasHits   uint64
+	HasMisses uint64
+	// CallbackTimeouts counts eviction callbacks abandoned after exceeding WithCallbackTimeout.
+	CallbackTimeouts uint64
+	// WriteLockWaits, WriteLockWaitNanos and MaxWriteLockWaitNanos describe how long Push and
+	// PushEvicting waited to acquire the write lock: the number of measured waits, their total
+	// and the longest one. The average wait is WriteLockWaitNanos / WriteLockWaits. They stay
+	// zero unless WithLatencyStats is set.
+	WriteLockWaits        uint64
+	WriteLockWaitNanos    uint64
+	MaxWriteLockWaitNanos uint64
+}
+
+// counters holds the live counters behind Stats. All fields are updated atomically.
+type counters struct {
+	hits             atomic.Uint64
+	misses           atomic.Uint64
+	hasHits          atomic.Uint64
+	hasMisses        atomic.Uint64
+	callbackTimeouts atomic.Uint64
+	lockWaits        atomic.Uint64
+	lockWaitNanos    atomic.Uint64
+	maxLockWaitNanos atomic.Uint64
+}
+
+// Stats returns a snapshot of the cache's counters. Counters are read atomically one by one,
+// so under concurrent load the fields may not describe the exact same instant.
+func (c *RingCache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:             c.stats.hits.Load(),
+		Misses:           c.stats.misses.Load(),
+		HasHits:          c.stats.hasHits.Load(),
+		HasMisses:        c.stats.hasMisses.Load(),
+		CallbackTimeouts: c.stats.callbackTimeouts.Load(),
+
+		WriteLockWaits:        c.stats.lockWaits.Load(),
+		WriteLockWaitNanos:    c.stats.lockWaitNanos.Load(),
+		MaxWriteLockWaitNanos: c.stats.maxLockWaitNanos.Load(),
+	}
+}
+
+// lockTimed acquires the write lock and, under WithLatencyStats, records how long that took.
+func (c *RingCache[K, V]) lockTimed() {
+	if !c.hooks.Load().latencyStats {
+		c.mu.Lock()
+		return
+	}
+	start := time.Now()
+	c.mu.Lock()
+	wait := uint64(time.Since(start))
+	c.stats.lockWaits.Add(1)
+	c.stats.lockWaitNanos.Add(wait)
+	for {
+		cur := c.stats.maxLockWaitNanos.Load()
+		if wait <= cur || c.stats.maxLockWaitNanos.CompareAndSwap(cur, wait) {
+			return
+		}
+	}
+}
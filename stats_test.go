@@ -0,0 +1,200 @@
+package ringcache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestCallbackTimeout_AbandonsSlowCallback(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	cb := func(int, string) { <-release }
+
+	rc, err := ringcache.NewWithEvictCallback[int, string](1, cb,
+		ringcache.WithCallbackTimeout[int, string](10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rc.Push(1, "one")
+
+	done := make(chan struct{})
+	go func() {
+		rc.Push(2, "two") // evicts 1; callback blocks until released
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Push blocked on a hung callback")
+	}
+	if got := rc.Stats().CallbackTimeouts; got != 1 {
+		t.Fatalf("CallbackTimeouts = %d, want 1", got)
+	}
+}
+
+func TestCallbackTimeout_FastCallbackNotCounted(t *testing.T) {
+	var calls int
+	rc, _ := ringcache.NewWithEvictCallback[int, string](1, func(int, string) { calls++ },
+		ringcache.WithCallbackTimeout[int, string](time.Second))
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	if calls != 1 {
+		t.Fatalf("callback calls = %d, want 1", calls)
+	}
+	if got := rc.Stats().CallbackTimeouts; got != 0 {
+		t.Fatalf("CallbackTimeouts = %d, want 0", got)
+	}
+}
+
+func TestCallbackTimeout_Negative(t *testing.T) {
+	if _, err := ringcache.New[int, string](1, ringcache.WithCallbackTimeout[int, string](-time.Second)); err == nil {
+		t.Fatalf("expected error for negative timeout")
+	}
+}
+
+func TestEvictionAgeHistogram(t *testing.T) {
+	rc, _ := ringcache.New[int, string](4, ringcache.WithEvictionAgeHistogram[int, string]())
+	for i := 0; i < 10; i++ {
+		rc.Push(i, "v") // from i=4 on, each push evicts an entry aged 4
+	}
+	rc.Delete(9) // not a ring eviction
+
+	h := rc.EvictionAgeHistogram()
+	if len(h) != 64 {
+		t.Fatalf("histogram has %d buckets, want 64", len(h))
+	}
+	// Age 4 falls in bucket 2: [4, 8).
+	if h[2] != 6 {
+		t.Fatalf("bucket[2] = %d, want 6 (histogram %v)", h[2], h[:4])
+	}
+	var total uint64
+	for _, n := range h {
+		total += n
+	}
+	if total != 6 {
+		t.Fatalf("total evictions recorded = %d, want 6", total)
+	}
+
+	// Re-pushing a key restarts its age.
+	rc2, _ := ringcache.New[int, string](2, ringcache.WithEvictionAgeHistogram[int, string]())
+	rc2.Push(1, "a")
+	rc2.Push(2, "b")
+	rc2.Push(1, "a") // re-push: 1 becomes the newest entry
+	rc2.Push(3, "c") // evicts 2 at age 2 -> bucket 1
+	rc2.Push(4, "d") // evicts 1 at age 2 counted from its re-push (4 from its first push) -> bucket 1
+	if h := rc2.EvictionAgeHistogram(); h[1] != 2 || h[0] != 0 {
+		t.Fatalf("buckets = %v, want [0 2 ...]", h[:3])
+	}
+}
+
+func TestEvictionAgeHistogram_Disabled(t *testing.T) {
+	rc, _ := ringcache.New[int, string](1)
+	if h := rc.EvictionAgeHistogram(); h != nil {
+		t.Fatalf("expected nil histogram without the option, got %v", h)
+	}
+}
+
+func TestStats_HasCountedSeparately(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2)
+	rc.Push(1, "one")
+	rc.Load(1)
+	rc.Load(2)
+	rc.Load(2)
+	rc.Has(1)
+	rc.Has(1)
+	rc.Has(3)
+
+	got := rc.Stats()
+	want := ringcache.Stats{Hits: 1, Misses: 2, HasHits: 2, HasMisses: 1}
+	if got != want {
+		t.Fatalf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLatencyStats(t *testing.T) {
+	rc, _ := ringcache.New[int, string](2, ringcache.WithLatencyStats[int, string]())
+	rc.Push(1, "one")
+	rc.PushEvicting(2, "two")
+	st := rc.Stats()
+	if st.WriteLockWaits != 2 {
+		t.Fatalf("WriteLockWaits = %d, want 2", st.WriteLockWaits)
+	}
+	if st.MaxWriteLockWaitNanos > st.WriteLockWaitNanos {
+		t.Fatalf("max wait %d exceeds total %d", st.MaxWriteLockWaitNanos, st.WriteLockWaitNanos)
+	}
+
+	// Without the option nothing is measured.
+	plain, _ := ringcache.New[int, string](1)
+	plain.Push(1, "one")
+	if st := plain.Stats(); st.WriteLockWaits != 0 || st.WriteLockWaitNanos != 0 {
+		t.Fatalf("latency stats recorded without WithLatencyStats: %+v", st)
+	}
+}
+
+func TestLowHitRatioWarning(t *testing.T) {
+	var warnings []float64
+	rc, _ := ringcache.New[int, int](1,
+		ringcache.WithLowHitRatioWarning[int, int](0.5, 4, func(r float64) { warnings = append(warnings, r) }))
+	rc.Push(1, 1)
+
+	loads := func(keys ...int) {
+		for _, k := range keys {
+			rc.Load(k)
+		}
+	}
+	loads(1, 2, 2, 2) // ratio 0.25: warn
+	loads(2, 2, 2, 2) // still low: rate-limited
+	loads(1, 1, 1, 2) // recovered: re-arms
+	loads(2, 2, 2)    // window not complete yet
+	if len(warnings) != 1 || warnings[0] != 0.25 {
+		t.Fatalf("warnings = %v, want [0.25]", warnings)
+	}
+	loads(2) // completes a window with ratio 0
+	if len(warnings) != 2 || warnings[1] != 0 {
+		t.Fatalf("warnings = %v, want [0.25 0]", warnings)
+	}
+
+	if _, err := ringcache.New[int, int](1,
+		ringcache.WithLowHitRatioWarning[int, int](0.5, 0, func(float64) {})); err == nil {
+		t.Fatalf("expected error for a zero window")
+	}
+}
+
+func TestWithOnHit(t *testing.T) {
+	hits := map[string]int{}
+	rc, _ := ringcache.New[string, int](2,
+		ringcache.WithOnHit[string, int](func(k string, v int) { hits[k] += v }))
+	rc.Push("a", 10)
+	rc.Load("a")
+	rc.Load("a")
+	rc.Load("missing")
+	rc.LoadOrDefault("a", 0)
+	rc.Has("a")
+	if len(hits) != 1 || hits["a"] != 30 {
+		t.Fatalf("hits = %v, want map[a:30]", hits)
+	}
+}
+
+func TestWithOnMiss_FallbackTier(t *testing.T) {
+	slow := map[string]int{"b": 2}
+	var misses []string
+	var rc *ringcache.RingCache[string, int]
+	rc, _ = ringcache.New[string, int](2, ringcache.WithOnMiss[string, int](func(k string) {
+		misses = append(misses, k)
+		if v, ok := slow[k]; ok {
+			rc.Push(k, v) // the hook runs outside the lock, so it may write
+		}
+	}))
+	rc.Push("a", 1)
+	rc.Load("a")
+	rc.Load("b")
+	rc.Has("c")
+	if len(misses) != 1 || misses[0] != "b" {
+		t.Fatalf("misses = %v, want [b]", misses)
+	}
+	if v, ok := rc.Load("b"); !ok || v != 2 {
+		t.Fatalf("fallback did not populate b: %d, %v", v, ok)
+	}
+}
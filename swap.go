@@ -0,0 +1,98 @@
+package ringcache
+
+import "time"
+
+// Swap atomically exchanges the contents of c and other, including their capacities, so a
+// cache built in the background can replace a live one without readers ever seeing a partially
+// filled state. Per-entry state (pins, versions, sequence numbers, insertion times, expiries)
+// moves with the entries; configuration (callbacks, options, secondary indexes, statistics)
+// stays with each cache, and each cache's indexes are rebuilt over its new entries. Entries
+// moving into a cache created WithSequence from one without it are numbered afresh in ring
+// order, and into one created WithInsertTimestamps they are stamped with the time of the Swap.
+//
+// No eviction callback fires: entries are moved, not evicted. Both write locks are held for the
+// exchange, always acquired in the order the caches were created, so concurrent Swaps of the
+// same pair in opposite directions cannot deadlock. Swapping a cache with itself is a no-op;
+// if either cache is frozen nothing is exchanged and ErrFrozen is returned.
+func (c *RingCache[K, V]) Swap(other *RingCache[K, V]) error {
+	if c == other {
+		return nil
+	}
+	var cEdge, oEdge func()
+	defer func() { runHook(cEdge); runHook(oEdge) }() // runs after both unlocks below
+	first, second := c, other
+	if second.id < first.id {
+		first, second = second, first
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	if c.frozen || other.frozen {
+		return ErrFrozen
+	}
+
+	cSeq, oSeq := c.seqs != nil, other.seqs != nil
+	cIns, oIns := c.inserted != nil, other.inserted != nil
+	c.capacity, other.capacity = other.capacity, c.capacity
+	c.next, other.next = other.next, c.next
+	c.keys, other.keys = other.keys, c.keys
+	c.occupied, other.occupied = other.occupied, c.occupied
+	c.items, other.items = other.items, c.items
+	c.pos, other.pos = other.pos, c.pos
+	c.pinned, other.pinned = other.pinned, c.pinned
+	c.seqs, other.seqs = other.seqs, c.seqs
+	c.inserted, other.inserted = other.inserted, c.inserted
+	c.expires, other.expires = other.expires, c.expires
+	c.minExpiry, other.minExpiry = other.minExpiry, c.minExpiry
+	c.versions, other.versions = other.versions, c.versions
+	cSize, oSize := c.size.Load(), other.size.Load()
+	c.size.Store(oSize)
+	other.size.Store(cSize)
+
+	// Keep sequence numbers unique and increasing in both caches.
+	c.seq = max(c.seq, other.seq)
+	other.seq = c.seq
+	c.adoptSequences(cSeq)
+	other.adoptSequences(oSeq)
+	c.adoptInsertTimes(cIns)
+	other.adoptInsertTimes(oIns)
+	c.rebuildIndexes()
+	other.rebuildIndexes()
+
+	c.gen.Add(1)
+	other.gen.Add(1)
+	cEdge, oEdge = c.fullEdge(), other.fullEdge()
+	return nil
+}
+
+// adoptSequences reconciles the sequence map received in a Swap with whether this cache
+// tracks sequence numbers. Caller must hold c.mu.
+func (c *RingCache[K, V]) adoptSequences(tracked bool) {
+	switch {
+	case !tracked:
+		c.seqs = nil
+	case c.seqs == nil:
+		c.seqs = make(map[K]uint64, c.capacity)
+		for _, e := range c.entries() {
+			c.seq++
+			c.seqs[e.Key] = c.seq
+		}
+	}
+}
+
+// adoptInsertTimes is adoptSequences for WithInsertTimestamps. Entries received from a cache
+// that kept no timestamps are stamped with the time of the Swap. Caller must hold c.mu.
+func (c *RingCache[K, V]) adoptInsertTimes(tracked bool) {
+	switch {
+	case !tracked:
+		c.inserted = nil
+	case c.inserted == nil:
+		c.inserted = make(map[K]time.Time, c.capacity)
+		now := c.now()
+		for k := range c.items {
+			c.inserted[k] = now
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package ringcache_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestSwap_ExchangesContentsAndCapacity(t *testing.T) {
+	var evicted int
+	cb := func(int, string) { evicted++ }
+	live, _ := ringcache.NewWithEvictCallback[int, string](2, cb)
+	live.Push(1, "old")
+
+	fresh, _ := ringcache.New[int, string](3)
+	fresh.Push(10, "a")
+	fresh.Push(20, "b")
+	fresh.Push(30, "c")
+	fresh.Pin(20)
+
+	if err := live.Swap(fresh); err != nil {
+		t.Fatalf("Swap: %v", err)
+	}
+	if evicted != 0 {
+		t.Fatalf("Swap must not fire eviction callbacks, got %d", evicted)
+	}
+	if live.Capacity() != 3 || live.Size() != 3 || !live.Has(20) || live.Has(1) {
+		t.Fatalf("live after swap: cap=%d size=%d", live.Capacity(), live.Size())
+	}
+	if fresh.Capacity() != 2 || fresh.Size() != 1 || !fresh.Has(1) {
+		t.Fatalf("fresh after swap: cap=%d size=%d", fresh.Capacity(), fresh.Size())
+	}
+
+	// Ring order and pins travel with the entries; the callback stays with live.
+	live.Push(40, "d") // evicts 10
+	live.Push(50, "e") // skips pinned 20, evicts 30
+	if live.Has(10) || live.Has(30) || !live.Has(20) {
+		t.Fatalf("unexpected eviction order after swap")
+	}
+	if evicted != 2 {
+		t.Fatalf("live should keep its callback, got %d calls", evicted)
+	}
+	for _, rc := range []*ringcache.RingCache[int, string]{live, fresh} {
+		if err := rc.Healthy(); err != nil {
+			t.Fatalf("unhealthy after swap: %v", err)
+		}
+	}
+}
+
+func TestSwap_SequenceTracking(t *testing.T) {
+	seq, _ := ringcache.New[int, string](2, ringcache.WithSequence[int, string]())
+	seq.Push(1, "a")
+	seq.Push(2, "b")
+	plain, _ := ringcache.New[int, string](2)
+	plain.Push(3, "c")
+	plain.Push(4, "d")
+
+	if err := seq.Swap(plain); err != nil {
+		t.Fatalf("Swap: %v", err)
+	}
+	s3, ok3 := seq.Sequence(3)
+	s4, ok4 := seq.Sequence(4)
+	if !ok3 || !ok4 || s3 <= 2 || s4 <= s3 {
+		t.Fatalf("incoming entries should get fresh increasing sequences: (%d,%v) (%d,%v)", s3, ok3, s4, ok4)
+	}
+	if _, ok := plain.Sequence(1); ok {
+		t.Fatalf("cache without WithSequence must not report sequences")
+	}
+	if err := seq.Healthy(); err != nil {
+		t.Fatalf("unhealthy after swap: %v", err)
+	}
+}
+
+func TestSwap_FrozenAndSelf(t *testing.T) {
+	a, _ := ringcache.New[int, string](1)
+	b, _ := ringcache.New[int, string](1)
+	a.Push(1, "a")
+	if err := a.Swap(a); err != nil || !a.Has(1) {
+		t.Fatalf("self swap should be a no-op, err=%v", err)
+	}
+	b.Freeze()
+	if err := a.Swap(b); !errors.Is(err, ringcache.ErrFrozen) {
+		t.Fatalf("expected ErrFrozen, got %v", err)
+	}
+	if !a.Has(1) {
+		t.Fatalf("failed swap must leave contents in place")
+	}
+}
+
+func TestSwap_OppositeDirectionsNoDeadlock(t *testing.T) {
+	a, _ := ringcache.New[int, int](4)
+	b, _ := ringcache.New[int, int](8)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if i == 0 {
+					_ = a.Swap(b)
+				} else {
+					_ = b.Swap(a)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if a.Capacity()+b.Capacity() != 12 {
+		t.Fatalf("capacities lost in concurrent swaps: %d + %d", a.Capacity(), b.Capacity())
+	}
+}
+
+func TestSizeAndCapacity_ConsistentAcrossSwap(t *testing.T) {
+	small, _ := ringcache.New[int, int](2)
+	big, _ := ringcache.New[int, int](100)
+	small.Push(1, 1)
+	small.Push(2, 2)
+	big.Push(1, 1)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = small.Swap(big)
+			}
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		size, capacity := small.SizeAndCapacity()
+		if !(size == 2 && capacity == 2) && !(size == 1 && capacity == 100) {
+			t.Fatalf("torn read: size %d, capacity %d", size, capacity)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestSwap_InsertTimestamps(t *testing.T) {
+	live, _ := ringcache.New[int, int](2, ringcache.WithInsertTimestamps[int, int]())
+	fresh, _ := ringcache.New[int, int](2)
+	fresh.Push(1, 1)
+	if err := live.Swap(fresh); err != nil {
+		t.Fatalf("Swap: %v", err)
+	}
+	if _, ok := live.OldestAge(); !ok {
+		t.Fatal("entries swapped in must be stamped")
+	}
+	if _, ok := fresh.OldestAge(); ok {
+		t.Fatal("a cache without WithInsertTimestamps must not report ages")
+	}
+	if err := live.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,154 @@
+package ringcache
+
+import "time"
+
+// WithDefaultTTL makes every Push stamp its entry with an expiry ttl from now, with the
+// semantics described at PushWithTTL, which overrides it per call. 0 (the default) means
+// entries never expire.
+func WithDefaultTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.defaultTTL = ttl }
+}
+
+// WithClock replaces time.Now as the source of the current time for expiry and insertion
+// timestamps, e.g. with a fake clock in tests. The clock is expected not to go backwards.
+func WithClock[K comparable, V any](now func() time.Time) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.clock = now }
+}
+
+// NewWithTTL creates a RingCache whose entries all expire ttl after their Push, like New with
+// WithDefaultTTL(ttl). Options in opts take precedence over ttl.
+func NewWithTTL[K comparable, V any](capacity int, ttl time.Duration, opts ...Option[K, V]) (*RingCache[K, V], error) {
+	return New(capacity, append([]Option[K, V]{WithDefaultTTL[K, V](ttl)}, opts...)...)
+}
+
+// PushWithTTL is Push for an entry that expires ttl from now, regardless of its ring position.
+// Once expired the entry counts as absent for Load and Has, which remove it lazily, invoking the
+// eviction callback like Delete (the callback is not told why the entry left). Until then it
+// keeps its slot, but a Push that would evict a live entry reclaims the slot of an expired one
+// instead, oldest first. A ttl <= 0 stores an entry that never expires, even under
+// WithDefaultTTL; a later Push of the same key replaces the expiry with the default (none
+// without WithDefaultTTL), while an update skipped by WithSkipNoopUpdates keeps it.
+// Size and whole-cache reads such as RangeErr and State count expired entries until they are
+// removed (Keys, Values, Entries and Range skip them); DeleteExpired removes them all at once.
+func (c *RingCache[K, V]) PushWithTTL(key K, value V, ttl time.Duration) (evicted bool) {
+	c.lockTimed()
+	victims, stored, _ := c.store(nil, key, value)
+	if stored {
+		if ttl > 0 {
+			c.setExpiry(key, ttl)
+		} else {
+			delete(c.expires, key)
+		}
+	}
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(victims...)
+	runHook(edge)
+	return len(victims) > 0
+}
+
+// DeleteExpired removes every expired entry, invoking the eviction callback for each, and
+// returns how many it removed. It walks the entries with a TTL under the write lock; call it
+// periodically to keep Size accurate when expired entries are rarely read.
+func (c *RingCache[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	if c.frozen || len(c.expires) == 0 {
+		c.mu.Unlock()
+		return 0
+	}
+	now := c.now()
+	var removed []Entry[K, V]
+	for k, exp := range c.expires {
+		if !now.Before(exp) {
+			v, _ := c.remove(k)
+			removed = append(removed, Entry[K, V]{Key: k, Value: v})
+		}
+	}
+	if len(removed) > 0 {
+		c.gen.Add(1)
+	}
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(removed...)
+	runHook(edge)
+	return len(removed)
+}
+
+// now returns the current time from the WithClock clock.
+func (c *RingCache[K, V]) now() time.Time {
+	return c.clock()
+}
+
+// setExpiry makes key expire ttl from now. Caller must hold c.mu.
+func (c *RingCache[K, V]) setExpiry(key K, ttl time.Duration) {
+	c.setExpiryAt(key, c.now().Add(ttl))
+}
+
+// setExpiryAt makes key expire at exp, lowering minExpiry if needed. Caller must hold c.mu.
+func (c *RingCache[K, V]) setExpiryAt(key K, exp time.Time) {
+	if c.expires == nil {
+		c.expires = make(map[K]time.Time)
+	}
+	c.expires[key] = exp
+	if len(c.expires) == 1 || exp.Before(c.minExpiry) {
+		c.minExpiry = exp
+	}
+}
+
+// isExpired reports whether key has an expiry that has passed. Caller must hold c.mu.
+func (c *RingCache[K, V]) isExpired(key K) bool {
+	exp, ok := c.expires[key]
+	return ok && !c.now().Before(exp)
+}
+
+// expire removes key if it is still expired, for the lazy removal done by Load and Has.
+// It must be called without holding c.mu.
+func (c *RingCache[K, V]) expire(key K) {
+	c.mu.Lock()
+	if c.frozen || !c.isExpired(key) {
+		c.mu.Unlock()
+		return
+	}
+	val, _ := c.remove(key)
+	c.gen.Add(1)
+	edge := c.fullEdge()
+	c.mu.Unlock()
+
+	c.notifyEvicted(Entry[K, V]{Key: key, Value: val})
+	runHook(edge)
+}
+
+// expiredSlot returns the slot of the first expired entry in ring order starting at next, if
+// any. minExpiry is a lower bound on every expiry, so the ring is scanned only once it has
+// passed; a scan that finds nothing raises minExpiry to the earliest expiry it saw, keeping
+// Push O(1) until that entry expires. Caller must hold c.mu.
+func (c *RingCache[K, V]) expiredSlot() (int, bool) {
+	if len(c.expires) == 0 {
+		return 0, false
+	}
+	now := c.now()
+	if now.Before(c.minExpiry) {
+		return 0, false
+	}
+	var earliest time.Time
+	for i := 0; i < c.capacity; i++ {
+		s := (c.next + i) % c.capacity
+		if !c.occupied[s] {
+			continue
+		}
+		exp, ok := c.expires[c.keys[s]]
+		if !ok {
+			continue
+		}
+		if !now.Before(exp) {
+			return s, true
+		}
+		if earliest.IsZero() || exp.Before(earliest) {
+			earliest = exp
+		}
+	}
+	c.minExpiry = earliest
+	return 0, false
+}
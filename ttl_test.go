@@ -0,0 +1,385 @@
+package ringcache_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chi07/ringcache"
+)
+
+// fakeClock is a manually advanced clock for WithClock.
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) now() time.Time          { return f.t }
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func TestPushWithTTL_ExpiresLazily(t *testing.T) {
+	var evicted []string
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.NewWithEvictCallback[string, int](4, func(k string, _ int) { evicted = append(evicted, k) },
+		ringcache.WithClock[string, int](clk.now))
+	rc.PushWithTTL("short", 1, time.Millisecond)
+	rc.PushWithTTL("long", 2, time.Hour)
+	rc.Push("forever", 3)
+	rc.PushWithTTL("zero", 4, 0) // never expires
+
+	clk.advance(time.Millisecond)
+	if v, ok := rc.Peek("short"); ok {
+		t.Fatalf("Peek of an expired entry = %d, want absent", v)
+	}
+	if rc.Size() != 4 {
+		t.Fatalf("Peek must not remove the expired entry, size = %d", rc.Size())
+	}
+	if _, ok := rc.Load("short"); ok {
+		t.Fatal("expired entry still loadable")
+	}
+	if !slices.Equal(evicted, []string{"short"}) || rc.Size() != 3 {
+		t.Fatalf("evicted = %v, size = %d; want [short], 3", evicted, rc.Size())
+	}
+	for _, k := range []string{"long", "forever", "zero"} {
+		if !rc.Has(k) {
+			t.Fatalf("%s should still be cached", k)
+		}
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPushWithTTL_HasRemoves(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](2, ringcache.WithClock[int, int](clk.now))
+	rc.PushWithTTL(1, 1, time.Millisecond)
+	clk.advance(time.Millisecond)
+	if rc.Has(1) || rc.Size() != 0 {
+		t.Fatalf("Has must report and remove the expired entry, size = %d", rc.Size())
+	}
+}
+
+func TestPushWithTTL_PushClearsExpiry(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](2, ringcache.WithClock[int, int](clk.now))
+	rc.PushWithTTL(1, 1, time.Millisecond)
+	rc.Push(1, 2)
+	clk.advance(time.Hour)
+	if v, ok := rc.Load(1); !ok || v != 2 {
+		t.Fatalf("Load(1) = %d, %v; a plain Push must clear the TTL", v, ok)
+	}
+}
+
+func TestPushWithTTL_ReclaimsExpiredSlot(t *testing.T) {
+	var evicted []string
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.NewWithEvictCallback[string, int](3, func(k string, _ int) { evicted = append(evicted, k) },
+		ringcache.WithClock[string, int](clk.now))
+	rc.Push("a", 1)
+	rc.PushWithTTL("b", 2, time.Millisecond)
+	rc.Push("c", 3)
+	clk.advance(time.Millisecond)
+
+	if k, _, ok := rc.WouldEvict("d"); !ok || k != "b" {
+		t.Fatalf("WouldEvict = %q, %v; want the expired b", k, ok)
+	}
+	if !rc.Push("d", 4) {
+		t.Fatal("Push should report the reclaimed entry as evicted")
+	}
+	if !slices.Equal(evicted, []string{"b"}) || !rc.Has("a") || !rc.Has("d") {
+		t.Fatalf("evicted = %v; the live oldest entry a must survive", evicted)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPushWithTTL_ReclaimKeepsRingOrder(t *testing.T) {
+	var evicted []string
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.NewWithEvictCallback[string, int](4, func(k string, _ int) { evicted = append(evicted, k) },
+		ringcache.WithClock[string, int](clk.now))
+	rc.Push("a", 1)
+	rc.PushWithTTL("b", 2, time.Millisecond)
+	rc.Push("c", 3)
+	rc.Push("d", 4)
+	clk.advance(time.Millisecond)
+
+	rc.Push("e", 5) // reclaims b
+	if keys := rc.Keys(); !slices.Equal(keys, []string{"a", "c", "d", "e"}) {
+		t.Fatalf("Keys = %v, want [a c d e]", keys)
+	}
+	rc.Push("f", 6)
+	if !slices.Equal(evicted, []string{"b", "a"}) {
+		t.Fatalf("evicted = %v, want [b a]: the next Push must evict the oldest entry", evicted)
+	}
+	if keys := rc.Keys(); !slices.Equal(keys, []string{"c", "d", "e", "f"}) {
+		t.Fatalf("Keys = %v, want [c d e f]", keys)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	var evicted int
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.NewWithEvictCallback[int, int](8, func(int, int) { evicted++ },
+		ringcache.WithClock[int, int](clk.now))
+	for i := 0; i < 4; i++ {
+		rc.PushWithTTL(i, i, time.Millisecond)
+	}
+	rc.PushWithTTL(10, 10, time.Hour)
+	rc.Push(11, 11)
+	clk.advance(time.Millisecond)
+
+	if n := rc.DeleteExpired(); n != 4 || evicted != 4 {
+		t.Fatalf("DeleteExpired = %d with %d callbacks, want 4, 4", n, evicted)
+	}
+	if rc.Size() != 2 || rc.DeleteExpired() != 0 {
+		t.Fatalf("size = %d, want 2 and nothing left to expire", rc.Size())
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewWithTTL(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, err := ringcache.NewWithTTL[string, int](4, time.Minute, ringcache.WithClock[string, int](clk.now))
+	if err != nil {
+		t.Fatalf("NewWithTTL: %v", err)
+	}
+	rc.Push("a", 1)
+	clk.advance(30 * time.Second)
+	rc.Push("b", 2)
+	rc.PushWithTTL("c", 3, time.Hour) // per-call override
+	rc.PushWithTTL("d", 4, 0)         // never expires
+
+	clk.advance(30 * time.Second)
+	if rc.Has("a") {
+		t.Fatal("a should expire exactly one minute after its Push")
+	}
+	if !rc.Has("b") {
+		t.Fatal("b expired early")
+	}
+	clk.advance(time.Hour)
+	if rc.Has("b") || rc.Has("c") || !rc.Has("d") {
+		t.Fatalf("after an hour: b=%v c=%v d=%v; want false, false, true", rc.Has("b"), rc.Has("c"), rc.Has("d"))
+	}
+	rc.Push("d", 5) // a plain Push takes the default TTL again
+	clk.advance(time.Minute)
+	if _, ok := rc.Load("d"); ok {
+		t.Fatal("re-pushed d should have the default TTL")
+	}
+}
+
+func TestDefaultTTL_Validation(t *testing.T) {
+	if _, err := ringcache.NewWithTTL[int, int](2, -time.Second); err == nil {
+		t.Fatal("negative default TTL accepted")
+	}
+	rc, _ := ringcache.New[int, int](2)
+	if err := rc.Reconfigure(ringcache.WithClock[int, int](time.Now)); err == nil {
+		t.Fatal("Reconfigure accepted WithClock")
+	}
+	if err := rc.Reconfigure(ringcache.WithDefaultTTL[int, int](time.Minute)); err != nil {
+		t.Fatalf("Reconfigure(WithDefaultTTL): %v", err)
+	}
+}
+
+func TestOldestAge_Clock(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](2, ringcache.WithInsertTimestamps[int, int](), ringcache.WithClock[int, int](clk.now))
+	rc.Push(1, 1)
+	clk.advance(time.Minute)
+	if age, ok := rc.OldestAge(); !ok || age != time.Minute {
+		t.Fatalf("OldestAge = %v, %v; want 1m", age, ok)
+	}
+}
+
+func TestLoadAndTouch_KeepsExpiry(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](4, ringcache.WithClock[int, int](clk.now))
+	rc.PushWithTTL(1, 1, time.Second)
+	rc.Push(2, 2)
+	if !rc.Touched(1) {
+		t.Fatal("Touched(1) = false before expiry")
+	}
+	if !rc.Touched(2) {
+		t.Fatal("Touched(2) = false")
+	}
+	clk.advance(2 * time.Second)
+	if rc.Has(1) {
+		t.Fatal("promotion must keep the TTL")
+	}
+	if !rc.Has(2) {
+		t.Fatal("promotion must not add a TTL")
+	}
+}
+
+func TestLoadAndTouch_ExpiredIsMiss(t *testing.T) {
+	var evicted []int
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.NewWithEvictCallback[int, int](4, func(k, _ int) { evicted = append(evicted, k) },
+		ringcache.WithClock[int, int](clk.now))
+	rc.PushWithTTL(1, 1, time.Second)
+	clk.advance(time.Second)
+	if v, ok := rc.LoadAndTouch(1); ok {
+		t.Fatalf("LoadAndTouch of an expired entry = %d, true; want a miss", v)
+	}
+	if rc.Size() != 0 || !slices.Equal(evicted, []int{1}) {
+		t.Fatalf("size = %d, evicted = %v; want the expired entry removed", rc.Size(), evicted)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBinary_KeepsExpiries(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	src, _ := ringcache.New[int, int](4, ringcache.WithClock[int, int](clk.now))
+	src.PushWithTTL(1, 1, time.Second)
+	src.PushWithTTL(2, 2, time.Minute)
+	src.Push(3, 3)
+	clk.advance(time.Second) // 1 has expired but is still stored
+
+	var buf bytes.Buffer
+	if err := src.WriteBinary(&buf); err != nil {
+		t.Fatalf("WriteBinary: %v", err)
+	}
+	dst, _ := ringcache.New[int, int](4, ringcache.WithClock[int, int](clk.now),
+		ringcache.WithDefaultTTL[int, int](time.Second))
+	if err := dst.ReadBinary(&buf); err != nil {
+		t.Fatalf("ReadBinary: %v", err)
+	}
+	if got := dst.Keys(); !slices.Equal(got, []int{2, 3}) {
+		t.Fatalf("Keys after ReadBinary = %v, want [2 3]", got)
+	}
+	clk.advance(time.Hour)
+	if dst.Has(2) {
+		t.Fatal("restored entry must keep its expiry")
+	}
+	if !dst.Has(3) {
+		t.Fatal("restored entry without a TTL must not get the default TTL")
+	}
+}
+
+func TestAccessors_SkipExpired(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](4, ringcache.WithClock[int, int](clk.now))
+	rc.Push(1, 1)
+	rc.PushWithTTL(2, 2, time.Second)
+	rc.Push(3, 3)
+	clk.advance(time.Second)
+
+	if _, ok := rc.Entry(2); ok {
+		t.Fatal("Entry reports an expired entry")
+	}
+	if p, ok := rc.PeekPos(2); ok || p != -1 {
+		t.Fatalf("PeekPos of an expired entry = (%d, %v), want (-1, false)", p, ok)
+	}
+	if _, slot, _, ok := rc.LoadDebug(2); ok || slot != 1 {
+		t.Fatalf("LoadDebug of an expired entry = slot %d, ok %v; want slot 1, ok false", slot, ok)
+	}
+	if got := rc.SortedKeys(func(a, b int) bool { return a < b }); !slices.Equal(got, []int{1, 3}) {
+		t.Fatalf("SortedKeys = %v, want [1 3]", got)
+	}
+	for _, e := range rc.Sample(4) {
+		if e.Key == 2 {
+			t.Fatal("Sample chose an expired entry")
+		}
+	}
+	var seen []int
+	rc.RangeBatched(1, func(batch []ringcache.Entry[int, int]) bool {
+		for _, e := range batch {
+			seen = append(seen, e.Key)
+		}
+		return true
+	})
+	if !slices.Equal(seen, []int{1, 3}) {
+		t.Fatalf("RangeBatched saw %v, want [1 3]", seen)
+	}
+}
+
+func TestEntry_ExpiresAt(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](2, ringcache.WithClock[int, int](clk.now))
+	rc.PushWithTTL(1, 1, time.Minute)
+	rc.Push(2, 2)
+	if info, _ := rc.Entry(1); !info.ExpiresAt.Equal(clk.t.Add(time.Minute)) {
+		t.Fatalf("ExpiresAt = %v, want %v", info.ExpiresAt, clk.t.Add(time.Minute))
+	}
+	if info, _ := rc.Entry(2); !info.ExpiresAt.IsZero() {
+		t.Fatalf("ExpiresAt without a TTL = %v, want zero", info.ExpiresAt)
+	}
+}
+
+func TestPushWithTTL_ReclaimAfterEarlierExpiryAdded(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](3, ringcache.WithClock[int, int](clk.now),
+		ringcache.WithDefaultTTL[int, int](time.Hour))
+	rc.Push(1, 1)
+	rc.Push(2, 2)
+	rc.Push(3, 3)
+	rc.Push(4, 4) // nothing has expired: evicts 1
+	rc.PushWithTTL(5, 5, time.Second)
+	clk.advance(2 * time.Second)
+	rc.Push(6, 6) // must reclaim the slot of 5, which expires before everything else
+	if got := rc.Keys(); !slices.Equal(got, []int{3, 4, 6}) {
+		t.Fatalf("Keys = %v, want [3 4 6]", got)
+	}
+}
+
+func TestPopNewest_SkipsExpired(t *testing.T) {
+	var evicted []string
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.NewWithEvictCallback[string, int](4, func(k string, _ int) { evicted = append(evicted, k) },
+		ringcache.WithClock[string, int](clk.now))
+	rc.Push("a", 1)
+	rc.Push("b", 2)
+	rc.PushWithTTL("old", 3, time.Second)
+	clk.advance(time.Second)
+
+	if k, v, ok := rc.PopNewest(); !ok || k != "b" || v != 2 {
+		t.Fatalf("PopNewest = (%q, %d, %v), want (\"b\", 2, true)", k, v, ok)
+	}
+	if !slices.Equal(evicted, []string{"old", "b"}) {
+		t.Fatalf("evicted %v, want [old b]", evicted)
+	}
+	if got := rc.Keys(); !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("Keys = %v, want [a]", got)
+	}
+	if err := rc.Healthy(); err != nil {
+		t.Fatal(err)
+	}
+	rc.PushWithTTL("gone", 4, time.Second)
+	clk.advance(time.Second)
+	rc.Delete("a")
+	if _, _, ok := rc.PopNewest(); ok || rc.Size() != 0 {
+		t.Fatalf("PopNewest with only expired entries = %v, size %d; want false, 0", ok, rc.Size())
+	}
+}
+
+func TestByIndex_SkipsExpired(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[string, session](4, ringcache.WithClock[string, session](clk.now),
+		ringcache.WithIndex[string, session]("user", byUser))
+	rc.PushWithTTL("old", session{user: "alice"}, time.Second)
+	rc.Push("b", session{user: "alice"})
+	clk.advance(time.Second)
+	if got := rc.ByIndex("user", "alice"); !slices.Equal(got, []string{"b"}) {
+		t.Fatalf("ByIndex = %v, want [b]", got)
+	}
+}
+
+func TestOldestAge_SkipsExpired(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1000, 0)}
+	rc, _ := ringcache.New[int, int](4, ringcache.WithClock[int, int](clk.now),
+		ringcache.WithInsertTimestamps[int, int]())
+	rc.PushWithTTL(1, 1, 10*time.Second)
+	clk.advance(10 * time.Second)
+	rc.Push(2, 2)
+	clk.advance(5 * time.Second)
+	if age, ok := rc.OldestAge(); !ok || age != 5*time.Second {
+		t.Fatalf("OldestAge = %v, %v; want 5s, the age of the live entry", age, ok)
+	}
+}
@@ -0,0 +1,31 @@
+package ringcache
+
+// Version returns the current version of key's entry, or false if key is absent. A key starts
+// at version 1 when it is inserted, and every update of its value (Push of an existing key,
+// SetValue, CompareVersionAndSet) increments it. Promotion through Touched or LoadAndTouch does
+// not change the value and keeps the version. Versions restart at 1 if a key is removed and
+// pushed again, so they only order updates within one residency of the key.
+func (c *RingCache[K, V]) Version(key K) (uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.versions[key]
+	return v, ok
+}
+
+// CompareVersionAndSet replaces key's value in place, like SetValue, only if the entry's version
+// still equals expectedVersion, and reports whether it did. On success the version increments.
+// Because it compares opaque versions rather than values it works for any V, and lets several
+// goroutines do read-modify-write updates safely: read with Load and Version, compute, and retry
+// if CompareVersionAndSet returns false. It returns false for absent keys and frozen caches.
+func (c *RingCache[K, V]) CompareVersionAndSet(key K, expectedVersion uint64, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		return false
+	}
+	if v, ok := c.versions[key]; !ok || v != expectedVersion {
+		return false
+	}
+	c.setValue(key, value)
+	return true
+}
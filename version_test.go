@@ -0,0 +1,79 @@
+package ringcache_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestVersion_Lifecycle(t *testing.T) {
+	rc, _ := ringcache.New[string, int](2)
+	if _, ok := rc.Version("a"); ok {
+		t.Fatalf("absent key should have no version")
+	}
+	rc.Push("a", 1)
+	if v, _ := rc.Version("a"); v != 1 {
+		t.Fatalf("version after insert = %d, want 1", v)
+	}
+	rc.Push("a", 2)
+	_ = rc.SetValue("a", 3)
+	if v, _ := rc.Version("a"); v != 3 {
+		t.Fatalf("version after two updates = %d, want 3", v)
+	}
+	rc.Touched("a")
+	if v, _ := rc.Version("a"); v != 3 {
+		t.Fatalf("promotion must keep the version, got %d", v)
+	}
+	rc.Delete("a")
+	rc.Push("a", 4)
+	if v, _ := rc.Version("a"); v != 1 {
+		t.Fatalf("version after re-insert = %d, want 1", v)
+	}
+}
+
+func TestCompareVersionAndSet(t *testing.T) {
+	rc, _ := ringcache.New[string, []int](2)
+	rc.Push("a", []int{1})
+	if rc.CompareVersionAndSet("a", 2, []int{2}) {
+		t.Fatalf("stale version must not update")
+	}
+	if !rc.CompareVersionAndSet("a", 1, []int{2}) {
+		t.Fatalf("current version should update")
+	}
+	if v, _ := rc.Version("a"); v != 2 {
+		t.Fatalf("version = %d, want 2", v)
+	}
+	if rc.CompareVersionAndSet("missing", 1, nil) {
+		t.Fatalf("absent key must not be set")
+	}
+	rc.Freeze()
+	if rc.CompareVersionAndSet("a", 2, []int{3}) {
+		t.Fatalf("frozen cache must not be updated")
+	}
+}
+
+func TestCompareVersionAndSet_ConcurrentIncrements(t *testing.T) {
+	rc, _ := ringcache.New[string, int](1)
+	rc.Push("n", 0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for {
+					ver, _ := rc.Version("n")
+					n, _ := rc.Load("n")
+					if rc.CompareVersionAndSet("n", ver, n+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n, _ := rc.Load("n"); n != 800 {
+		t.Fatalf("n = %d, want 800", n)
+	}
+}
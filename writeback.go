@@ -0,0 +1,82 @@
+package ringcache
+
+import "sync"
+
+// writeBack queues evicted entries and hands them to a user flush function in batches.
+type writeBack[K comparable, V any] struct {
+	mu        sync.Mutex // guards queue; held while flush runs so batches never overlap
+	threshold int
+	queue     []Entry[K, V]
+	flush     func([]Entry[K, V]) error
+	onError   func([]Entry[K, V], error)
+}
+
+// WithWriteBack turns the cache into a write-behind buffer in front of a slower store.
+// Every entry that reaches the eviction path (ring eviction, Delete, Clear) is queued,
+// and as soon as threshold entries are waiting, the queue is passed to flush in eviction order.
+// Flush drains the queue on demand, e.g. before shutdown.
+//
+// flush runs on the goroutine whose operation filled the queue, after the cache lock has been
+// released. Batches never overlap: an evicting writer that arrives while a flush is running
+// waits for it to finish. For the same reason flush and the dead-letter handler must not call
+// Flush or any method that can remove an entry (a Push into a full cache, Delete, Clear, ...):
+// the call would wait for the running flush, that is for itself, and deadlock. Reads are safe.
+//
+// A batch that flush rejects is not retried: it is dropped after being passed to the
+// dead-letter handler set with WithWriteBackErrorHandler, if any. This keeps the queue bounded
+// by threshold even when the backing store is down. A nil flush disables write-back.
+func WithWriteBack[K comparable, V any](threshold int, flush func([]Entry[K, V]) error) Option[K, V] {
+	return func(cfg *config[K, V]) {
+		cfg.flushThreshold = threshold
+		cfg.flush = flush
+	}
+}
+
+// WithWriteBackErrorHandler registers a dead-letter handler receiving every batch that the
+// WithWriteBack flush function failed to store, together with the returned error.
+func WithWriteBackErrorHandler[K comparable, V any](h func(batch []Entry[K, V], err error)) Option[K, V] {
+	return func(cfg *config[K, V]) { cfg.flushError = h }
+}
+
+// Flush passes all queued write-back entries to the flush function and returns its error.
+// It is a no-op returning nil when nothing is queued or the cache was not created WithWriteBack.
+// Under WithAsyncPush it first waits until every PushAsync issued before the call is applied,
+// so it must not be called from a callback run by an async push (see WithAsyncPush).
+func (c *RingCache[K, V]) Flush() error {
+	if c.async != nil {
+		c.async.wait()
+	}
+	if c.wb == nil {
+		return nil
+	}
+	c.wb.mu.Lock()
+	defer c.wb.mu.Unlock()
+	return c.wb.flushQueued()
+}
+
+// add queues entries and flushes once the threshold is reached.
+func (wb *writeBack[K, V]) add(entries []Entry[K, V]) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	wb.queue = append(wb.queue, entries...)
+	if len(wb.queue) >= wb.threshold {
+		// The error has already been reported to the dead-letter handler.
+		_ = wb.flushQueued()
+	}
+}
+
+// flushQueued hands the whole queue to flush. Caller must hold wb.mu.
+func (wb *writeBack[K, V]) flushQueued() error {
+	if len(wb.queue) == 0 {
+		return nil
+	}
+	batch := wb.queue
+	wb.queue = nil
+	if err := wb.flush(batch); err != nil {
+		if wb.onError != nil {
+			wb.onError(batch, err)
+		}
+		return err
+	}
+	return nil
+}
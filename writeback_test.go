@@ -0,0 +1,90 @@
+package ringcache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chi07/ringcache"
+)
+
+func TestWriteBack_FlushesAtThreshold(t *testing.T) {
+	var batches [][]ringcache.Entry[int, string]
+	flush := func(b []ringcache.Entry[int, string]) error {
+		batches = append(batches, b)
+		return nil
+	}
+	rc, err := ringcache.New[int, string](2, ringcache.WithWriteBack[int, string](2, flush))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rc.Push(1, "one")
+	rc.Push(2, "two")
+	rc.Push(3, "three") // evicts 1, queued
+	if len(batches) != 0 {
+		t.Fatalf("flushed before threshold: %v", batches)
+	}
+	rc.Delete(2) // queued, reaches threshold
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("batches = %v, want one batch of 2", batches)
+	}
+	if batches[0][0].Key != 1 || batches[0][1].Key != 2 {
+		t.Fatalf("batch order = %v, want keys 1 then 2", batches[0])
+	}
+
+	rc.Clear() // queues 3
+	if err := rc.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(batches) != 2 || len(batches[1]) != 1 || batches[1][0].Key != 3 {
+		t.Fatalf("manual flush mismatch: %v", batches)
+	}
+
+	// Nothing left: Flush is a no-op.
+	if err := rc.Flush(); err != nil || len(batches) != 2 {
+		t.Fatalf("empty Flush should not call flush (err=%v, batches=%d)", err, len(batches))
+	}
+}
+
+func TestWriteBack_FailedBatchGoesToDeadLetter(t *testing.T) {
+	errStore := errors.New("store down")
+	var dead []ringcache.Entry[int, string]
+	rc, _ := ringcache.New[int, string](1,
+		ringcache.WithWriteBack[int, string](10, func([]ringcache.Entry[int, string]) error { return errStore }),
+		ringcache.WithWriteBackErrorHandler[int, string](func(b []ringcache.Entry[int, string], err error) {
+			if !errors.Is(err, errStore) {
+				t.Errorf("unexpected error in handler: %v", err)
+			}
+			dead = append(dead, b...)
+		}),
+	)
+
+	rc.Push(1, "one")
+	rc.Push(2, "two") // evicts 1
+	if err := rc.Flush(); !errors.Is(err, errStore) {
+		t.Fatalf("expected flush error, got %v", err)
+	}
+	if len(dead) != 1 || dead[0].Key != 1 {
+		t.Fatalf("dead letters = %v, want [1]", dead)
+	}
+
+	// The failed batch is not retried.
+	if err := rc.Flush(); err != nil {
+		t.Fatalf("expected empty queue after failed flush, got %v", err)
+	}
+}
+
+func TestWriteBack_InvalidThreshold(t *testing.T) {
+	_, err := ringcache.New[int, string](1,
+		ringcache.WithWriteBack[int, string](0, func([]ringcache.Entry[int, string]) error { return nil }))
+	if err == nil {
+		t.Fatalf("expected error for threshold=0")
+	}
+}
+
+func TestWriteBack_FlushWithoutOption(t *testing.T) {
+	rc, _ := ringcache.New[int, string](1)
+	if err := rc.Flush(); err != nil {
+		t.Fatalf("Flush without write-back should be a no-op, got %v", err)
+	}
+}